@@ -0,0 +1,171 @@
+package volley
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies the API token for each request. When the API
+// rejects a token with 401 Unauthorized, the client calls Refresh and retries
+// the request once if the token changed. Implementations must be safe for
+// concurrent use.
+type TokenProvider interface {
+	// Token returns the current token
+	Token() (string, error)
+	// Refresh returns a new token after the current one was rejected
+	Refresh() (string, error)
+}
+
+// WithTokenProvider reads the API token from p on every request instead of
+// using the token passed to NewClient
+func WithTokenProvider(p TokenProvider) ClientOption {
+	return func(c *Client) {
+		c.tokens = p
+	}
+}
+
+// StaticToken is a fixed API token. It is the provider NewClient uses.
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// Refresh returns the same token, as a static token cannot be rotated
+func (t StaticToken) Refresh() (string, error) {
+	return string(t), nil
+}
+
+// EnvToken reads the API token from the named environment variable on every request
+type EnvToken string
+
+// Token returns the variable's value
+func (e EnvToken) Token() (string, error) {
+	token := strings.TrimSpace(os.Getenv(string(e)))
+	if token == "" {
+		return "", fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return token, nil
+}
+
+// Refresh reads the variable again
+func (e EnvToken) Refresh() (string, error) {
+	return e.Token()
+}
+
+// FileToken reads the API token from a file and re-reads it when the file
+// changes, so a rotated Kubernetes secret mount is picked up without a
+// restart. Surrounding whitespace is trimmed.
+type FileToken struct {
+	path    string
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileToken creates a FileToken for path. The file is read on first use.
+func NewFileToken(path string) *FileToken {
+	return &FileToken{path: path}
+}
+
+// Token returns the token, re-reading the file if it changed since the last read
+func (f *FileToken) Token() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if f.token != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+	return f.read(info)
+}
+
+// Refresh re-reads the file
+func (f *FileToken) Refresh() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	return f.read(info)
+}
+
+// read loads the token from the file. The caller must hold f.mu.
+func (f *FileToken) read(info os.FileInfo) (string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", f.path)
+	}
+	f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
+
+// CommandToken runs a command and uses its trimmed standard output as the API
+// token, e.g. a secret manager CLI. The output is reused until Refresh runs
+// the command again.
+type CommandToken struct {
+	name  string
+	args  []string
+	mu    sync.Mutex
+	token string
+}
+
+// NewCommandToken creates a CommandToken. The command runs on first use.
+func NewCommandToken(name string, args ...string) *CommandToken {
+	return &CommandToken{name: name, args: args}
+}
+
+// Token returns the command's output, running it if it has not run yet
+func (c *CommandToken) Token() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" {
+		return c.token, nil
+	}
+	return c.run()
+}
+
+// Refresh runs the command again
+func (c *CommandToken) Refresh() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.run()
+}
+
+// run executes the command. The caller must hold c.mu.
+func (c *CommandToken) run() (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("token command failed: %w", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("token command printed no token")
+	}
+	c.token = token
+	return token, nil
+}
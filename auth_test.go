@@ -0,0 +1,137 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// rotatingToken returns its old token until refreshed
+type rotatingToken struct {
+	mu        sync.Mutex
+	token     string
+	next      string
+	refreshes int
+}
+
+func (r *rotatingToken) Token() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.token, nil
+}
+
+func (r *rotatingToken) Refresh() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshes++
+	r.token = r.next
+	return r.token, nil
+}
+
+func setupTokenEmulator(t *testing.T, token string) string {
+	t.Helper()
+	emu := emulator.New(&emulator.Options{Token: token})
+	server := httptest.NewServer(emu)
+	t.Cleanup(func() {
+		server.Close()
+		emu.Close()
+	})
+	return server.URL
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	baseURL := setupTokenEmulator(t, "new-token")
+
+	provider := &rotatingToken{token: "old-token", next: "new-token"}
+	metrics := &recordingMetrics{}
+	client := volley.NewClient("", volley.WithBaseURL(baseURL), volley.WithTokenProvider(provider), volley.WithMetrics(metrics))
+
+	if _, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"}); err != nil {
+		t.Fatalf("Expected the request to succeed after refreshing, got %v", err)
+	}
+	if provider.refreshes != 1 {
+		t.Errorf("Expected 1 refresh, got %d", provider.refreshes)
+	}
+	if len(metrics.requests) != 2 || metrics.requests[0].StatusCode != http.StatusUnauthorized || metrics.requests[1].Retries != 1 {
+		t.Errorf("Expected a 401 followed by a retry, got %+v", metrics.requests)
+	}
+	if sources, _ := client.ListSources(1); len(sources) != 1 {
+		t.Errorf("Expected the retried create to send its body once, got %d sources", len(sources))
+	}
+}
+
+func TestStaticTokenIsNotRetried(t *testing.T) {
+	baseURL := setupTokenEmulator(t, "right-token")
+
+	metrics := &recordingMetrics{}
+	client := volley.NewClient("wrong-token", volley.WithBaseURL(baseURL), volley.WithMetrics(metrics))
+
+	_, err := client.ListProjects()
+	var apiErr *volley.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("Expected an APIError with status 401, got %v", err)
+	}
+	if len(metrics.requests) != 1 {
+		t.Errorf("Expected no retry when the token did not change, got %d requests", len(metrics.requests))
+	}
+}
+
+func TestFileTokenRotation(t *testing.T) {
+	baseURL := setupTokenEmulator(t, "rotated-token")
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client := volley.NewClient("", volley.WithBaseURL(baseURL), volley.WithTokenProvider(volley.NewFileToken(path)))
+
+	if _, err := client.ListProjects(); err == nil {
+		t.Fatal("Expected the first token to be rejected")
+	}
+
+	if err := os.WriteFile(path, []byte("rotated-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("Expected the rotated token to be used, got %v", err)
+	}
+
+	if _, err := volley.NewFileToken(filepath.Join(t.TempDir(), "missing")).Token(); err == nil {
+		t.Error("Expected an error for a missing token file")
+	}
+}
+
+func TestEnvToken(t *testing.T) {
+	t.Setenv("VOLLEY_TEST_TOKEN", "  env-token\n")
+	token, err := volley.EnvToken("VOLLEY_TEST_TOKEN").Token()
+	if err != nil || token != "env-token" {
+		t.Errorf("Expected env-token, got %q (%v)", token, err)
+	}
+
+	t.Setenv("VOLLEY_TEST_TOKEN", "")
+	if _, err := volley.EnvToken("VOLLEY_TEST_TOKEN").Refresh(); err == nil {
+		t.Error("Expected an error for an unset variable")
+	}
+}
+
+func TestCommandToken(t *testing.T) {
+	provider := volley.NewCommandToken("echo", "cmd-token")
+	token, err := provider.Token()
+	if err != nil || token != "cmd-token" {
+		t.Errorf("Expected cmd-token, got %q (%v)", token, err)
+	}
+	if token, err := provider.Refresh(); err != nil || token != "cmd-token" {
+		t.Errorf("Expected Refresh to run the command again, got %q (%v)", token, err)
+	}
+
+	if _, err := volley.NewCommandToken("false").Token(); err == nil {
+		t.Error("Expected an error when the command fails")
+	}
+}
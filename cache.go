@@ -0,0 +1,204 @@
+package volley
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a stored GET response used to answer conditional requests
+type CachedResponse struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"stored_at"`
+}
+
+// ResponseCache stores GET responses keyed by organization and URL.
+// Implementations must be safe for concurrent use.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// WithResponseCache enables HTTP response caching. GET responses carrying an
+// ETag or Last-Modified header are stored and revalidated with If-None-Match
+// and If-Modified-Since; a 304 Not Modified is answered from the cache. Other
+// methods bypass the cache.
+func WithResponseCache(cache ResponseCache) ClientOption {
+	return func(c *Client) {
+		c.responseCache = cache
+	}
+}
+
+// cacheKey identifies a GET request by organization context and URL
+func (c *Client) cacheKey(reqURL string) string {
+	return c.orgKey() + " " + reqURL
+}
+
+// revalidate adds conditional headers for a cached response
+func (cached *CachedResponse) revalidate(req *http.Request) {
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+}
+
+// cacheResponse answers a 304 from the cached response, or stores a
+// cacheable 200 response and returns it with its body intact
+func (c *Client) cacheResponse(key string, cached *CachedResponse, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      resp.Proto,
+			ProtoMajor: resp.ProtoMajor,
+			ProtoMinor: resp.ProtoMinor,
+			Header:     cached.Header.Clone(),
+			Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+			Request:    resp.Request,
+		}, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.responseCache.Set(key, &CachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		StoredAt:     time.Now(),
+	})
+	return resp, nil
+}
+
+// MemoryCache is an in-memory ResponseCache that evicts the least recently
+// used entry once it holds maxEntries
+type MemoryCache struct {
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]*CachedResponse
+	// order lists keys from least to most recently used
+	order []string
+}
+
+// NewMemoryCache creates a MemoryCache. A maxEntries of zero or less is unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, entries: make(map[string]*CachedResponse)}
+}
+
+// Get returns the cached response for key
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp, ok := m.entries[key]
+	if ok {
+		m.touch(key)
+	}
+	return resp, ok
+}
+
+// Set stores a response, evicting the least recently used entry if full
+func (m *MemoryCache) Set(key string, resp *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		delete(m.entries, m.order[0])
+		m.order = m.order[1:]
+	}
+	m.entries[key] = resp
+	m.touch(key)
+}
+
+// Len returns the number of cached responses
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func (m *MemoryCache) touch(key string) {
+	for i, k := range m.order {
+		if k == key {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	m.order = append(m.order, key)
+}
+
+// DiskCache is a ResponseCache storing one JSON file per response in a
+// directory, so cached responses survive restarts. Read and write errors are
+// treated as cache misses.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a DiskCache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the cached response for key
+func (d *DiskCache) Get(key string) (*CachedResponse, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var resp CachedResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Set stores a response, replacing the file atomically
+func (d *DiskCache) Set(key string, resp *CachedResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
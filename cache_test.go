@@ -0,0 +1,159 @@
+package volley_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// statusRecorder serves the emulator and records response status codes
+type statusRecorder struct {
+	emu      *emulator.Server
+	mu       sync.Mutex
+	statuses []int
+}
+
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+	s.emu.ServeHTTP(rw, r)
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		s.statuses = append(s.statuses, rw.status)
+		s.mu.Unlock()
+	}
+}
+
+func (s *statusRecorder) last() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statuses[len(s.statuses)-1]
+}
+
+func TestResponseCacheETag(t *testing.T) {
+	backend := &statusRecorder{emu: emulator.New(nil)}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+	defer backend.emu.Close()
+
+	cache := volley.NewMemoryCache(0)
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithResponseCache(cache))
+
+	client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if cache.Len() != 0 {
+		t.Fatalf("Expected mutating requests to bypass the cache, got %d entries", cache.Len())
+	}
+
+	first, err := client.ListSources(1)
+	if err != nil {
+		t.Fatalf("ListSources failed: %v", err)
+	}
+	if backend.last() != http.StatusOK || cache.Len() != 1 {
+		t.Fatalf("Expected a 200 to be cached, got status %d and %d entries", backend.last(), cache.Len())
+	}
+
+	second, err := client.ListSources(1)
+	if err != nil {
+		t.Fatalf("ListSources from cache failed: %v", err)
+	}
+	if backend.last() != http.StatusNotModified {
+		t.Errorf("Expected a conditional request answered with 304, got %d", backend.last())
+	}
+	if len(second) != 1 || second[0].ID != first[0].ID {
+		t.Errorf("Expected the cached sources, got %+v", second)
+	}
+
+	// A change produces a new ETag and fresh data
+	client.CreateSource(1, volley.CreateSourceRequest{Name: "github"})
+	third, _ := client.ListSources(1)
+	if backend.last() != http.StatusOK || len(third) != 2 {
+		t.Errorf("Expected fresh data after a change, got status %d and %d sources", backend.last(), len(third))
+	}
+
+	// The organization is part of the key
+	client.SetOrganizationID(1)
+	client.ListSources(1)
+	if cache.Len() != 2 {
+		t.Errorf("Expected a separate entry per organization, got %d entries", cache.Len())
+	}
+}
+
+func TestResponseCacheLastModified(t *testing.T) {
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") == modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"connection":{"id":5,"status":"enabled"}}`))
+	}))
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithResponseCache(volley.NewMemoryCache(10)))
+	for i := 0; i < 3; i++ {
+		conn, err := client.GetConnection(5)
+		if err != nil {
+			t.Fatalf("GetConnection failed: %v", err)
+		}
+		if conn.ID != 5 || conn.Status != "enabled" {
+			t.Errorf("Expected connection 5, got %+v", conn)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected every call to revalidate, got %d requests", requests)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := volley.NewMemoryCache(2)
+	cache.Set("a", &volley.CachedResponse{ETag: "a"})
+	cache.Set("b", &volley.CachedResponse{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", &volley.CachedResponse{ETag: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected the recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := volley.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	cache.Set("default https://api/sources", &volley.CachedResponse{ETag: `"v1"`, Body: []byte(`{"sources":[]}`)})
+
+	reopened, _ := volley.NewDiskCache(dir)
+	resp, ok := reopened.Get("default https://api/sources")
+	if !ok || resp.ETag != `"v1"` || string(resp.Body) != `{"sources":[]}` {
+		t.Errorf("Expected the stored response after reopening, got %+v, %v", resp, ok)
+	}
+	if _, ok := reopened.Get("missing"); ok {
+		t.Error("Expected a miss for an unknown key")
+	}
+}
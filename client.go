@@ -20,9 +20,30 @@ const (
 // Client is the main Volley API client
 type Client struct {
 	baseURL        string
-	apiToken       string
+	tokens         TokenProvider
 	organizationID *uint64
+	projectID      uint64
 	httpClient     *http.Client
+	lookup         *lookupCache
+	responseCache  ResponseCache
+	metrics        Metrics
+	retry          RetryPolicy
+
+	// Resource services
+	Organizations    *OrganizationsService
+	Projects         *ProjectsService
+	Sources          *SourcesService
+	Destinations     *DestinationsService
+	Connections      *ConnectionsService
+	Events           *EventsService
+	DeliveryAttempts *DeliveryAttemptsService
+	Ingestion        *IngestionService
+	Tokens           *TokensService
+}
+
+// service is the base of the resource services, which share their client
+type service struct {
+	client *Client
 }
 
 // ClientOption is a function that configures a Client
@@ -32,9 +53,11 @@ type ClientOption func(*Client)
 func NewClient(apiToken string, opts ...ClientOption) *Client {
 	client := &Client{
 		baseURL:    DefaultBaseURL,
-		apiToken:   apiToken,
+		tokens:     StaticToken(apiToken),
 		httpClient: &http.Client{Timeout: DefaultTimeout},
+		lookup:     newLookupCache(DefaultLookupCacheTTL),
 	}
+	client.initServices()
 
 	for _, opt := range opts {
 		opt(client)
@@ -43,6 +66,29 @@ func NewClient(apiToken string, opts ...ClientOption) *Client {
 	return client
 }
 
+// initServices points the resource services at c
+func (c *Client) initServices() {
+	c.Organizations = (*OrganizationsService)(&service{c})
+	c.Projects = (*ProjectsService)(&service{c})
+	c.Sources = (*SourcesService)(&service{c})
+	c.Destinations = (*DestinationsService)(&service{c})
+	c.Connections = (*ConnectionsService)(&service{c})
+	c.Events = (*EventsService)(&service{c})
+	c.DeliveryAttempts = (*DeliveryAttemptsService)(&service{c})
+	c.Ingestion = (*IngestionService)(&service{c})
+	c.Tokens = (*TokensService)(&service{c})
+}
+
+// withOrganization returns a shallow copy of c that sends requests to
+// another organization. The copy shares c's HTTP client, token provider and
+// caches, which are keyed by organization.
+func (c *Client) withOrganization(orgID uint64) *Client {
+	clone := *c
+	clone.organizationID = &orgID
+	clone.initServices()
+	return &clone
+}
+
 // WithBaseURL sets a custom base URL for the client
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
@@ -101,36 +147,100 @@ func (c *Client) doRequest(method, path string, body interface{}, queryParams ma
 		reqURL = u.String()
 	}
 
-	// Create request body
-	var reqBody io.Reader
+	// Marshal request body
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if jsonData, err = json.Marshal(body); err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	// Create request
+	token, err := c.tokens.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API token: %w", err)
+	}
+
+	// Look up a cached GET response to revalidate
+	var cached *CachedResponse
+	cacheKey := ""
+	if c.responseCache != nil && method == http.MethodGet {
+		cacheKey = c.cacheKey(reqURL)
+		if entry, ok := c.responseCache.Get(cacheKey); ok {
+			cached = entry
+		}
+	}
+
+	// Changes invalidate cached lookups, whether or not they succeeded
+	defer c.lookup.invalidate(method, path)
+
+	var resp *http.Response
+	refreshed := false
+	backoffs := 0
+	for retries := 0; ; retries++ {
+		req, err := c.newRequest(method, reqURL, jsonData, token)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			cached.revalidate(req)
+		}
+
+		// Perform request
+		resp, err = c.send(req, operationName(method, path), int64(len(jsonData)), retries)
+		if err != nil {
+			if backoffs < c.retry.MaxRetries && c.retry.retryable(method, 0) {
+				time.Sleep(c.retry.delay(backoffs, nil))
+				backoffs++
+				continue
+			}
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		// Retry once if the token provider has a new token
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			if newToken, err := c.tokens.Refresh(); err == nil && newToken != token {
+				resp.Body.Close()
+				token = newToken
+				continue
+			}
+		}
+
+		if backoffs < c.retry.MaxRetries && c.retry.retryable(method, resp.StatusCode) {
+			delay := c.retry.delay(backoffs, resp)
+			resp.Body.Close()
+			time.Sleep(delay)
+			backoffs++
+			continue
+		}
+		break
+	}
+
+	if cacheKey != "" {
+		return c.cacheResponse(cacheKey, cached, resp)
+	}
+	return resp, nil
+}
+
+// newRequest creates an authenticated API request
+func (c *Client) newRequest(method, reqURL string, jsonData []byte, token string) (*http.Request, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
 	req, err := http.NewRequest(method, reqURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 	if c.organizationID != nil {
 		req.Header.Set("X-Organization-ID", fmt.Sprintf("%d", *c.organizationID))
 	}
-
-	// Perform request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
-	return resp, nil
+	return req, nil
 }
 
 // parseResponse parses the JSON response into the target struct
@@ -147,6 +257,7 @@ func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 		if err := json.Unmarshal(body, &apiError); err != nil {
 			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 		}
+		apiError.Status = resp.StatusCode
 		return &apiError
 	}
 
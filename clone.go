@@ -0,0 +1,137 @@
+package volley
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URLRewrite replaces From with To in destination URLs when cloning a project,
+// e.g. {From: "api.example.com", To: "staging-api.example.com"}
+type URLRewrite struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// CloneProjectOptions configures CloneProject
+type CloneProjectOptions struct {
+	// Name of the new project (default: the source project's name)
+	Name string
+	// URLRewrites are applied in order to every destination URL
+	URLRewrites []URLRewrite
+}
+
+// CloneProjectResult maps the objects of the source project to their clones
+type CloneProjectResult struct {
+	Project        Project           `json:"project"`
+	SourceIDs      map[uint64]uint64 `json:"source_ids"`      // old ID -> new ID
+	DestinationIDs map[uint64]uint64 `json:"destination_ids"` // old ID -> new ID
+	ConnectionIDs  map[uint64]uint64 `json:"connection_ids"`  // old ID -> new ID
+	IngestionURLs  map[uint64]string `json:"ingestion_urls"`  // new source ID -> ingestion URL
+}
+
+// CloneProject copies a project's sources, destinations and connections into a
+// new project in targetOrgID. If targetOrgID is 0 the current organization is used.
+// Secrets are not copied and must be set on the new sources and destinations
+// separately, after which signature verification and destination auth can be
+// re-enabled.
+// On failure the partially cloned project is left in place and returned in
+// the result along with the error.
+func (c *Client) CloneProject(srcProjectID, targetOrgID uint64, opts *CloneProjectOptions) (*CloneProjectResult, error) {
+	if opts == nil {
+		opts = &CloneProjectOptions{}
+	}
+
+	name := opts.Name
+	if name == "" {
+		projects, err := c.Projects.List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
+		for _, p := range projects {
+			if p.ID == srcProjectID {
+				name = p.Name
+				break
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("project %d not found", srcProjectID)
+		}
+	}
+
+	live, err := c.fetchLiveTopology(srcProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &TopologySpec{}
+	spec.fill(live)
+	for i := range spec.Sources {
+		// Secrets are set separately, so signatures cannot be verified yet
+		spec.Sources[i].WebhookSecret = ""
+		spec.Sources[i].AuthPassword = ""
+		spec.Sources[i].AuthKey = ""
+		spec.Sources[i].VerifySignature = nil
+	}
+	for i := range spec.Destinations {
+		// Without their secrets, destinations are cloned without auth or mTLS
+		d := &spec.Destinations[i]
+		d.AuthType, d.AuthUsername, d.AuthPassword, d.AuthToken = "", "", "", ""
+		d.ClientCert, d.ClientKey = "", ""
+		for _, rw := range opts.URLRewrites {
+			spec.Destinations[i].URL = strings.ReplaceAll(spec.Destinations[i].URL, rw.From, rw.To)
+		}
+	}
+
+	// Create the clone through a copy of the client, so concurrent calls on c
+	// keep their organization
+	target := c
+	if targetOrgID != 0 {
+		target = c.withOrganization(targetOrgID)
+	}
+
+	project, err := target.Projects.Create(CreateProjectRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	result := &CloneProjectResult{
+		Project:        *project,
+		SourceIDs:      make(map[uint64]uint64),
+		DestinationIDs: make(map[uint64]uint64),
+		ConnectionIDs:  make(map[uint64]uint64),
+		IngestionURLs:  make(map[uint64]string),
+	}
+
+	plan, err := target.PlanTopology(project.ID, spec, nil)
+	if err != nil {
+		return result, err
+	}
+	if _, err := target.ApplyTopology(plan); err != nil {
+		return result, err
+	}
+
+	// Map by name against the new project's live state so objects that
+	// already existed in the target are included as well
+	cloned, err := target.fetchLiveTopology(project.ID)
+	if err != nil {
+		return result, err
+	}
+	for slug, src := range live.sources {
+		if clone, ok := cloned.sources[slug]; ok {
+			result.SourceIDs[src.ID] = clone.ID
+			result.IngestionURLs[clone.ID] = target.Ingestion.URL(clone.IngestionID)
+		}
+	}
+	for name, dest := range live.destinations {
+		if clone, ok := cloned.destinations[name]; ok {
+			result.DestinationIDs[dest.ID] = clone.ID
+		}
+	}
+	for key, conn := range live.connections {
+		if clone, ok := cloned.connections[key]; ok {
+			result.ConnectionIDs[conn.ID] = clone.ID
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package volley_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func TestCloneProject(t *testing.T) {
+	backend := newProjectBackend()
+	backend.projects = []volley.Project{{ID: 1, Name: "Production"}}
+	stripe := backend.addSource("stripe", 10)
+	api := backend.addDestination("api", "https://api.example.com/hooks", 5)
+	conn := backend.addConnection(stripe.ID, api.ID, 5, 3)
+
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithOrganizationID(1))
+
+	result, err := client.CloneProject(1, 2, &volley.CloneProjectOptions{
+		Name: "Staging",
+		URLRewrites: []volley.URLRewrite{
+			{From: "api.example.com", To: "staging-api.example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CloneProject failed: %v", err)
+	}
+
+	if result.Project.Name != "Staging" {
+		t.Errorf("Expected project name 'Staging', got %s", result.Project.Name)
+	}
+	if org := backend.orgs[result.Project.ID]; org != "2" {
+		t.Errorf("Expected project to be created in organization 2, got %q", org)
+	}
+	if client.OrganizationID() == nil || *client.OrganizationID() != 1 {
+		t.Errorf("Expected organization context to be restored to 1, got %v", client.OrganizationID())
+	}
+
+	newSourceID, ok := result.SourceIDs[stripe.ID]
+	if !ok || newSourceID == stripe.ID {
+		t.Fatalf("Expected source %d to be remapped, got %v", stripe.ID, result.SourceIDs)
+	}
+	newDestID, ok := result.DestinationIDs[api.ID]
+	if !ok {
+		t.Fatalf("Expected destination %d to be remapped, got %v", api.ID, result.DestinationIDs)
+	}
+	newConnID, ok := result.ConnectionIDs[conn.ID]
+	if !ok {
+		t.Fatalf("Expected connection %d to be remapped, got %v", conn.ID, result.ConnectionIDs)
+	}
+
+	if got := backend.destinations[newDestID].URL; got != "https://staging-api.example.com/hooks" {
+		t.Errorf("Expected rewritten URL, got %s", got)
+	}
+	clonedConn := backend.connections[newConnID]
+	if clonedConn.SourceID != newSourceID || clonedConn.DestinationID != newDestID {
+		t.Errorf("Expected cloned connection to reference cloned objects, got %+v", clonedConn)
+	}
+
+	url := result.IngestionURLs[newSourceID]
+	if !strings.HasPrefix(url, server.URL+"/hook/ing_stripe_") {
+		t.Errorf("Unexpected ingestion URL %s", url)
+	}
+}
+
+func TestCloneProjectKeepsClientOrganization(t *testing.T) {
+	backend := newProjectBackend()
+	backend.projects = []volley.Project{{ID: 1, Name: "Production"}}
+	backend.addSource("stripe", 10)
+
+	// While the clone creates its project in organization 2, another call on
+	// the same client must still go to organization 1
+	var client *volley.Client
+	var concurrentOrg string
+	server := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tokens" {
+			concurrentOrg = r.Header.Get("X-Organization-ID")
+			w.Write([]byte(`{"tokens":[]}`))
+			return
+		}
+		if r.Method == http.MethodPost && r.URL.Path == "/api/projects" {
+			if _, err := client.Tokens.List(); err != nil {
+				t.Errorf("Tokens.List failed: %v", err)
+			}
+		}
+		backend.ServeHTTP(w, r)
+	})
+	defer server.Close()
+
+	client = volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithOrganizationID(1))
+	if _, err := client.CloneProject(1, 2, &volley.CloneProjectOptions{Name: "Staging"}); err != nil {
+		t.Fatalf("CloneProject failed: %v", err)
+	}
+	if concurrentOrg != "1" {
+		t.Errorf("Expected the concurrent call to use organization 1, got %q", concurrentOrg)
+	}
+}
@@ -0,0 +1,145 @@
+// Command volley-exporter serves Volley project metrics to Prometheus.
+//
+// Usage:
+//
+//	volley-exporter [--profile NAME] [--addr :9799] [--project ID,...] [--interval 30s] [--window 5m]
+//
+// Credentials and defaults come from the same config profiles and VOLLEY_*
+// environment variables as the volley command. Metrics are served on /metrics.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/exporter"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("volley-exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":9799", "address to serve metrics on")
+	profileName := fs.String("profile", "", "config profile to use (default $VOLLEY_PROFILE or the config's default)")
+	baseURL := fs.String("base-url", "", "API base URL (default from the profile or "+volley.DefaultBaseURL+")")
+	org := fs.Uint64("org", 0, "organization ID (default from the profile or the token's default organization)")
+	projects := fs.String("project", "", "comma-separated project IDs to collect (default: the profile's project, or all projects)")
+	interval := fs.Duration("interval", exporter.DefaultInterval, "interval between collections")
+	window := fs.Duration("window", exporter.DefaultWindow, "lookback window for event counts and new delivery attempts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := volley.LoadConfig()
+	if err != nil {
+		return err
+	}
+	profile, err := cfg.Profile(*profileName)
+	if err != nil {
+		return err
+	}
+
+	projectIDs, err := parseIDs(*projects)
+	if err != nil {
+		return err
+	}
+	if len(projectIDs) == 0 && profile.ProjectID != 0 {
+		projectIDs = []uint64{profile.ProjectID}
+	}
+
+	clientMetrics := volley.NewMemoryMetrics()
+	opts := []volley.ClientOption{volley.WithMetrics(clientMetrics)}
+	if *baseURL != "" {
+		opts = append(opts, volley.WithBaseURL(*baseURL))
+	}
+	if *org != 0 {
+		opts = append(opts, volley.WithOrganizationID(*org))
+	}
+	client, err := profile.NewClient(opts...)
+	if err != nil {
+		return err
+	}
+
+	logger := log.New(stderr, "", log.LstdFlags)
+	exp := exporter.New(client, exporter.Options{
+		ProjectIDs: projectIDs,
+		Interval:   *interval,
+		Window:     *window,
+		OnError:    func(err error) { logger.Printf("collection failed: %v", err) },
+		// Report the exporter's own API usage alongside project health
+		ClientMetrics: clientMetrics,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `<html><body><h1>Volley Exporter</h1><p><a href="/metrics">Metrics</a></p></body></html>`)
+	})
+	server := &http.Server{Addr: *addr, Handler: mux}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go exp.Run(ctx)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+	logger.Printf("serving metrics on %s/metrics", *addr)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// parseIDs parses a comma-separated list of project IDs
+func parseIDs(s string) ([]uint64, error) {
+	var ids []uint64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid project ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func connectionsCommand() *command {
+	return &command{
+		name:    "connections",
+		summary: "Manage source to destination connections",
+		subcommands: []*command{
+			{name: "list", summary: "List connections in a project", run: connectionsList},
+			{name: "get", args: "<connection-id>", summary: "Show a connection", run: connectionsGet},
+			{name: "create", summary: "Connect a source to a destination", run: connectionsCreate},
+			{name: "update", args: "<connection-id>", summary: "Update a connection", run: connectionsUpdate},
+			{name: "delete", args: "<connection-id>", summary: "Delete a connection", run: connectionsDelete},
+		},
+	}
+}
+
+func connectionTable(connections ...volley.Connection) *table {
+	t := &table{headers: []string{"ID", "SOURCE", "DESTINATION", "STATUS", "EPS", "MAX RETRIES", "RULES"}}
+	for _, c := range connections {
+		t.add(formatID(c.ID), formatID(c.SourceID), formatID(c.DestinationID), c.Status,
+			strconv.Itoa(c.EPS), strconv.Itoa(c.MaxRetries), rulesSummary(c))
+	}
+	return t
+}
+
+// rulesSummary describes a connection's rules, e.g. "2 filters, map, exponential"
+func rulesSummary(c volley.Connection) string {
+	var parts []string
+	switch len(c.Filters) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 filter")
+	default:
+		parts = append(parts, fmt.Sprintf("%d filters", len(c.Filters)))
+	}
+	if c.Transform != nil {
+		parts = append(parts, string(c.Transform.Type))
+	}
+	if c.Retry != nil {
+		parts = append(parts, string(c.Retry.Type))
+	}
+	return orDash(strings.Join(parts, ", "))
+}
+
+// loadRules reads the --rules file, or returns nil if path is empty
+func loadRules(path string) (*volley.ConnectionRules, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return volley.LoadConnectionRulesFile(path)
+}
+
+func connectionsList(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	connections, err := client.Connections.List(projectID)
+	if err != nil {
+		return err
+	}
+	return a.render(connections, connectionTable(connections...))
+}
+
+func connectionsGet(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("connection", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	conn, err := client.Connections.Get(id)
+	if err != nil {
+		return err
+	}
+	return a.render(conn, connectionTable(*conn))
+}
+
+func connectionsCreate(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	var source, destination optionalUint64
+	fs.Var(&source, "source", "source ID (required)")
+	fs.Var(&destination, "destination", "destination ID (required)")
+	status := fs.String("status", "enabled", "connection status: enabled or disabled")
+	eps := fs.Int("eps", 10, "events per second")
+	maxRetries := fs.Int("max-retries", 3, "maximum delivery retries")
+	rulesFile := fs.String("rules", "", "YAML or JSON file with filters, transform and retry strategy")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	if !source.set || !destination.set {
+		return fmt.Errorf("--source and --destination are required")
+	}
+	rules, err := loadRules(*rulesFile)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	req := volley.CreateConnectionRequest{
+		SourceID:      source.value,
+		DestinationID: destination.value,
+		Status:        *status,
+		EPS:           *eps,
+		MaxRetries:    *maxRetries,
+	}
+	if rules != nil {
+		req.Filters, req.Transform, req.Retry = rules.Filters, rules.Transform, rules.Retry
+	}
+	conn, err := client.Connections.Create(projectID, req)
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+	return a.render(conn, connectionTable(*conn))
+}
+
+func connectionsUpdate(a *app, args []string) error {
+	fs := a.flags()
+	var eps, maxRetries optionalInt
+	fs.Var(&eps, "eps", "events per second")
+	fs.Var(&maxRetries, "max-retries", "maximum delivery retries")
+	status := fs.String("status", "", "connection status: enabled or disabled")
+	rulesFile := fs.String("rules", "", "YAML or JSON file replacing the filters, transform and retry strategy")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("connection", pos[0])
+	if err != nil {
+		return err
+	}
+	rules, err := loadRules(*rulesFile)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	req := volley.UpdateConnectionRequest{
+		Status:     *status,
+		EPS:        eps.ptr(),
+		MaxRetries: maxRetries.ptr(),
+	}
+	if rules != nil {
+		req.Filters, req.RemoveFilters = rules.Filters, len(rules.Filters) == 0
+		req.Transform, req.RemoveTransform = rules.Transform, rules.Transform == nil
+		req.Retry, req.RemoveRetry = rules.Retry, rules.Retry == nil
+	}
+	conn, err := client.Connections.Update(id, req)
+	if err != nil {
+		return fmt.Errorf("failed to update connection: %w", err)
+	}
+	return a.render(conn, connectionTable(*conn))
+}
+
+func connectionsDelete(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("connection", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Connections.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Deleted connection %d\n", id)
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func destinationsCommand() *command {
+	return &command{
+		name:    "destinations",
+		summary: "Manage webhook destinations",
+		subcommands: []*command{
+			{name: "list", summary: "List destinations in a project", run: destinationsList},
+			{name: "get", args: "<destination-id>", summary: "Show a destination", run: destinationsGet},
+			{name: "create", args: "<name>", summary: "Create a destination", run: destinationsCreate},
+			{name: "update", args: "<destination-id>", summary: "Update a destination", run: destinationsUpdate},
+			{name: "delete", args: "<destination-id>", summary: "Delete a destination", run: destinationsDelete},
+		},
+	}
+}
+
+func destinationTable(destinations ...volley.Destination) *table {
+	t := &table{headers: []string{"ID", "NAME", "METHOD", "URL", "EPS", "AUTH", "STATUS"}}
+	for _, d := range destinations {
+		t.add(formatID(d.ID), d.Name, orDash(d.Method), d.URL, strconv.Itoa(d.EPS), orDash(d.AuthType), d.Status)
+	}
+	return t
+}
+
+// readPEM reads a PEM file named by a flag, or returns "" if path is empty
+func readPEM(flagName, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("--%s: %w", flagName, err)
+	}
+	return string(data), nil
+}
+
+func destinationsList(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	destinations, err := client.Destinations.List(projectID)
+	if err != nil {
+		return err
+	}
+	return a.render(destinations, destinationTable(destinations...))
+}
+
+func destinationsGet(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("destination", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	dest, err := client.Destinations.Get(id)
+	if err != nil {
+		return err
+	}
+	return a.render(dest, destinationTable(*dest))
+}
+
+func destinationsCreate(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	url := fs.String("url", "", "destination URL (required)")
+	eps := fs.Int("eps", 10, "events per second")
+	method := fs.String("method", "POST", "HTTP method: POST, PUT or PATCH")
+	var headers headerFlag
+	fs.Var(&headers, "header", "static header \"Name: value\" (repeatable)")
+	timeout := fs.Int("timeout", 0, "delivery timeout in seconds (default: server default)")
+	authType := fs.String("auth-type", "none", "authentication type: none, bearer or basic")
+	username := fs.String("username", "", "basic auth username")
+	password := fs.String("password", "", "basic auth password")
+	token := fs.String("token", "", "bearer token")
+	certFile := fs.String("client-cert", "", "PEM client certificate file for mutual TLS")
+	keyFile := fs.String("client-key", "", "PEM client key file for mutual TLS")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	if *url == "" {
+		return fmt.Errorf("--url is required")
+	}
+	cert, err := readPEM("client-cert", *certFile)
+	if err != nil {
+		return err
+	}
+	key, err := readPEM("client-key", *keyFile)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	dest, err := client.Destinations.Create(projectID, volley.CreateDestinationRequest{
+		Name:           pos[0],
+		URL:            *url,
+		EPS:            *eps,
+		Method:         *method,
+		Headers:        headers.headers,
+		TimeoutSeconds: *timeout,
+		AuthType:       *authType,
+		AuthUsername:   *username,
+		AuthPassword:   volley.Secret(*password),
+		AuthToken:      volley.Secret(*token),
+		ClientCert:     cert,
+		ClientKey:      volley.Secret(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+	return a.render(dest, destinationTable(*dest))
+}
+
+func destinationsUpdate(a *app, args []string) error {
+	fs := a.flags()
+	var eps optionalInt
+	fs.Var(&eps, "eps", "events per second")
+	name := fs.String("name", "", "new destination name")
+	url := fs.String("url", "", "new destination URL")
+	status := fs.String("status", "", "destination status")
+	method := fs.String("method", "", "HTTP method: POST, PUT or PATCH")
+	var headers headerFlag
+	fs.Var(&headers, "header", "static header \"Name: value\", replacing existing headers (repeatable)")
+	noHeaders := fs.Bool("no-headers", false, "remove all static headers")
+	var timeout optionalInt
+	fs.Var(&timeout, "timeout", "delivery timeout in seconds (0: server default)")
+	authType := fs.String("auth-type", "", "authentication type: none, bearer or basic")
+	username := fs.String("username", "", "basic auth username")
+	password := fs.String("password", "", "basic auth password")
+	token := fs.String("token", "", "bearer token")
+	certFile := fs.String("client-cert", "", "PEM client certificate file for mutual TLS")
+	keyFile := fs.String("client-key", "", "PEM client key file for mutual TLS")
+	removeCert := fs.Bool("remove-client-cert", false, "stop presenting a client certificate")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("destination", pos[0])
+	if err != nil {
+		return err
+	}
+	if *noHeaders && headers.headers != nil {
+		return fmt.Errorf("--header and --no-headers cannot be combined")
+	}
+	cert, err := readPEM("client-cert", *certFile)
+	if err != nil {
+		return err
+	}
+	key, err := readPEM("client-key", *keyFile)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	dest, err := client.Destinations.Update(id, volley.UpdateDestinationRequest{
+		Name:             *name,
+		URL:              *url,
+		EPS:              eps.ptr(),
+		Status:           *status,
+		Method:           *method,
+		Headers:          headers.headers,
+		RemoveHeaders:    *noHeaders,
+		TimeoutSeconds:   timeout.ptr(),
+		AuthType:         *authType,
+		AuthUsername:     *username,
+		AuthPassword:     volley.Secret(*password),
+		AuthToken:        volley.Secret(*token),
+		ClientCert:       cert,
+		ClientKey:        volley.Secret(key),
+		RemoveClientCert: *removeCert,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update destination: %w", err)
+	}
+	return a.render(dest, destinationTable(*dest))
+}
+
+func destinationsDelete(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("destination", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Destinations.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete destination: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Deleted destination %d\n", id)
+	return nil
+}
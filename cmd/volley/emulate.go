@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func emulateCommand() *command {
+	return &command{
+		name:    "emulate",
+		summary: "Run a local Volley emulator for offline development",
+		run:     emulate,
+	}
+}
+
+func emulate(a *app, args []string) error {
+	fs := a.flags()
+	addr := fs.String("addr", "localhost:8787", "address to listen on")
+	token := fs.String("token", "", "only accept this API token (default: accept any token)")
+	topology := fs.String("topology", "", "topology file (YAML or JSON) to apply to the default project on start")
+	backoff := fs.Duration("retry-backoff", emulator.DefaultRetryBackoff, "delay before the first retry of a failed delivery")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var spec *volley.TopologySpec
+	if *topology != "" {
+		var err error
+		if spec, err = volley.LoadTopologyFile(*topology); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	emu := emulator.New(&emulator.Options{Token: *token, RetryBackoff: *backoff})
+	defer emu.Close()
+
+	server := &http.Server{Handler: emu}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(ln) }()
+
+	baseURL := "http://" + ln.Addr().String()
+	fmt.Fprintf(a.stderr, "Volley emulator listening on %s\n", baseURL)
+
+	if spec != nil {
+		apiToken := *token
+		if apiToken == "" {
+			apiToken = "emulator"
+		}
+		if err := seedEmulator(a, volley.NewClient(apiToken, volley.WithBaseURL(baseURL)), spec); err != nil {
+			server.Close()
+			return err
+		}
+	}
+	fmt.Fprintf(a.stderr, "Point the CLI at it with --base-url %s, or the SDK with volley.WithBaseURL\n", baseURL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// seedEmulator applies a topology to the emulator's default project and
+// prints the ingestion URL of each source
+func seedEmulator(a *app, client *volley.Client, spec *volley.TopologySpec) error {
+	const projectID = 1
+
+	plan, err := client.PlanTopology(projectID, spec, nil)
+	if err != nil {
+		return err
+	}
+	result, err := client.ApplyTopology(plan)
+	if err != nil {
+		return fmt.Errorf("failed to apply topology: %w", err)
+	}
+
+	fmt.Fprintf(a.stderr, "Applied %d changes to project %d\n", len(result.Applied), projectID)
+
+	urls := make(map[string]string)
+	t := &table{headers: []string{"SOURCE", "INGESTION URL"}}
+	for _, s := range spec.Sources {
+		urls[s.Slug] = client.Ingestion.URL(result.Sources[s.Slug].IngestionID)
+		t.add(s.Slug, urls[s.Slug])
+	}
+	return a.render(urls, t)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func eventsCommand() *command {
+	return &command{
+		name:    "events",
+		summary: "Inspect webhook events",
+		subcommands: []*command{
+			{name: "list", summary: "List events in a project", run: eventsList},
+			{name: "get", args: "<request-id>", summary: "Show an event with its delivery attempts", run: eventsGet},
+			{name: "tail", summary: "Stream new events as they arrive", run: eventsTail},
+		},
+	}
+}
+
+func attemptsCommand() *command {
+	return &command{
+		name:    "attempts",
+		summary: "Inspect delivery attempts",
+		subcommands: []*command{
+			{name: "list", summary: "List delivery attempts in a project", run: attemptsList},
+		},
+	}
+}
+
+func replayCommand() *command {
+	return &command{
+		name:    "replay",
+		args:    "<event-id>",
+		summary: "Replay an event to its destinations",
+		run:     replay,
+	}
+}
+
+func eventTable(events ...volley.Event) *table {
+	t := &table{headers: []string{"ID", "EVENT ID", "SOURCE", "STATUS", "ATTEMPTS", "CREATED"}}
+	for _, e := range events {
+		t.add(formatID(e.ID), e.EventID, formatID(e.SourceID), e.Status,
+			strconv.Itoa(len(e.DeliveryAttempts)), formatTime(e.CreatedAt))
+	}
+	return t
+}
+
+func attemptTable(attempts ...volley.DeliveryAttempt) *table {
+	t := &table{headers: []string{"ID", "EVENT ID", "CONNECTION", "STATUS", "CODE", "DURATION", "ERROR", "CREATED"}}
+	for _, at := range attempts {
+		t.add(formatID(at.ID), at.EventID, formatID(at.ConnectionID), at.Status, strconv.Itoa(at.StatusCode),
+			fmt.Sprintf("%dms", at.DurationMs), orDash(at.ErrorReason), formatTime(at.CreatedAt))
+	}
+	return t
+}
+
+func eventsList(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	var source, connection, destination optionalUint64
+	var start, end optionalTime
+	var limit, offset optionalInt
+	fs.Var(&source, "source", "filter by source ID")
+	fs.Var(&connection, "connection", "filter by connection ID")
+	fs.Var(&destination, "destination", "filter by destination ID")
+	status := fs.String("status", "", "filter by status: processed, pending, failed or dropped")
+	fs.Var(&start, "since", "only events after this time (RFC 3339 or a duration such as 1h)")
+	fs.Var(&end, "until", "only events before this time (RFC 3339 or a duration such as 1h)")
+	search := fs.String("search", "", "full-text search")
+	fs.Var(&limit, "limit", "maximum number of events")
+	fs.Var(&offset, "offset", "number of events to skip")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Events.List(projectID, &volley.ListEventsOptions{
+		SourceID:      source.ptr(),
+		ConnectionID:  connection.ptr(),
+		DestinationID: destination.ptr(),
+		Status:        *status,
+		StartTime:     start.ptr(),
+		EndTime:       end.ptr(),
+		Search:        *search,
+		Limit:         limit.ptr(),
+		Offset:        offset.ptr(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.render(resp, eventTable(resp.Requests...)); err != nil {
+		return err
+	}
+	if a.globals.output == "table" {
+		fmt.Fprintf(a.stderr, "Showing %d of %d events\n", len(resp.Requests), resp.Total)
+	}
+	return nil
+}
+
+func eventsGet(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("request", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	event, err := client.Events.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := a.render(event, eventTable(*event)); err != nil {
+		return err
+	}
+	if a.globals.output == "table" && len(event.DeliveryAttempts) > 0 {
+		fmt.Fprintln(a.stdout)
+		return a.render(nil, attemptTable(event.DeliveryAttempts...))
+	}
+	return nil
+}
+
+func attemptsList(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	var source, connection, destination optionalUint64
+	var start, end optionalTime
+	var limit, offset optionalInt
+	eventID := fs.String("event", "", "filter by event ID")
+	fs.Var(&source, "source", "filter by source ID")
+	fs.Var(&connection, "connection", "filter by connection ID")
+	fs.Var(&destination, "destination", "filter by destination ID")
+	status := fs.String("status", "", "filter by status: success or failed")
+	fs.Var(&start, "since", "only attempts after this time (RFC 3339 or a duration such as 1h)")
+	fs.Var(&end, "until", "only attempts before this time (RFC 3339 or a duration such as 1h)")
+	sort := fs.String("sort", "", "sort order: time, time_oldest, duration or status_code")
+	fs.Var(&limit, "limit", "maximum number of attempts")
+	fs.Var(&offset, "offset", "number of attempts to skip")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.DeliveryAttempts.List(projectID, &volley.ListDeliveryAttemptsOptions{
+		EventID:       *eventID,
+		SourceID:      source.ptr(),
+		DestinationID: destination.ptr(),
+		ConnectionID:  connection.ptr(),
+		Status:        *status,
+		StartTime:     start.ptr(),
+		EndTime:       end.ptr(),
+		Sort:          *sort,
+		Limit:         limit.ptr(),
+		Offset:        offset.ptr(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.render(resp, attemptTable(resp.Attempts...)); err != nil {
+		return err
+	}
+	if a.globals.output == "table" {
+		fmt.Fprintf(a.stderr, "Showing %d of %d attempts\n", len(resp.Attempts), resp.Total)
+	}
+	return nil
+}
+
+func replay(a *app, args []string) error {
+	fs := a.flags()
+	var destination, connection optionalUint64
+	fs.Var(&destination, "destination", "replay to a single destination")
+	fs.Var(&connection, "connection", "replay through a single connection")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.Events.Replay(volley.ReplayEventRequest{
+		EventID:       pos[0],
+		DestinationID: destination.ptr(),
+		ConnectionID:  connection.ptr(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay event: %w", err)
+	}
+
+	t := &table{headers: []string{"ATTEMPT", "STATUS", "CODE", "DURATION", "ERROR"}}
+	t.add(formatID(result.AttemptID), result.Status, strconv.Itoa(result.StatusCode),
+		fmt.Sprintf("%dms", result.DurationMs), orDash(result.ErrorReason))
+	return a.render(result, t)
+}
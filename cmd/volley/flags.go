@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// optionalUint64 is a uint64 flag that records whether it was set
+type optionalUint64 struct {
+	value uint64
+	set   bool
+}
+
+func (o *optionalUint64) String() string {
+	if !o.set {
+		return ""
+	}
+	return strconv.FormatUint(o.value, 10)
+}
+
+func (o *optionalUint64) Set(s string) error {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID %q", s)
+	}
+	o.value, o.set = v, true
+	return nil
+}
+
+// ptr returns a pointer to the value, or nil if the flag was not set
+func (o *optionalUint64) ptr() *uint64 {
+	if !o.set {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+// optionalInt is an int flag that records whether it was set
+type optionalInt struct {
+	value int
+	set   bool
+}
+
+func (o *optionalInt) String() string {
+	if !o.set {
+		return ""
+	}
+	return strconv.Itoa(o.value)
+}
+
+func (o *optionalInt) Set(s string) error {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	o.value, o.set = v, true
+	return nil
+}
+
+// ptr returns a pointer to the value, or nil if the flag was not set
+func (o *optionalInt) ptr() *int {
+	if !o.set {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+// optionalBool is a bool flag that records whether it was set
+type optionalBool struct {
+	value bool
+	set   bool
+}
+
+func (o *optionalBool) String() string {
+	if !o.set {
+		return ""
+	}
+	return strconv.FormatBool(o.value)
+}
+
+func (o *optionalBool) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", s)
+	}
+	o.value, o.set = v, true
+	return nil
+}
+
+// IsBoolFlag lets the flag be given without a value
+func (o *optionalBool) IsBoolFlag() bool {
+	return true
+}
+
+// ptr returns a pointer to the value, or nil if the flag was not set
+func (o *optionalBool) ptr() *bool {
+	if !o.set {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+// optionalTime is a time flag accepting RFC 3339 timestamps or a duration
+// relative to now, e.g. "1h" for one hour ago
+type optionalTime struct {
+	value time.Time
+	set   bool
+}
+
+func (o *optionalTime) String() string {
+	if !o.set {
+		return ""
+	}
+	return o.value.Format(time.RFC3339)
+}
+
+func (o *optionalTime) Set(s string) error {
+	if d, err := time.ParseDuration(s); err == nil {
+		o.value, o.set = time.Now().Add(-d), true
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("invalid time %q (use RFC 3339 or a duration such as 1h)", s)
+	}
+	o.value, o.set = t, true
+	return nil
+}
+
+// ptr returns a pointer to the value, or nil if the flag was not set
+func (o *optionalTime) ptr() *time.Time {
+	if !o.set {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+// headerFlag is a repeatable "Name: value" flag collecting static headers
+type headerFlag struct {
+	headers map[string]string
+}
+
+func (h *headerFlag) String() string {
+	names := make([]string, 0, len(h.headers))
+	for name, value := range h.headers {
+		names = append(names, name+": "+value)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (h *headerFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid header %q (use \"Name: value\")", s)
+	}
+	if h.headers == nil {
+		h.headers = make(map[string]string)
+	}
+	h.headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	return nil
+}
+
+// projectFlag registers the --project flag used by project-scoped commands
+func projectFlag(fs *flag.FlagSet) *optionalUint64 {
+	p := &optionalUint64{}
+	fs.Var(p, "project", "project ID (default from the profile)")
+	return p
+}
+
+// defaultProject sets p from the profile's project if --project was not given
+func (a *app) defaultProject(p *optionalUint64) error {
+	if p.set {
+		return nil
+	}
+	profile, err := a.config()
+	if err != nil {
+		return err
+	}
+	if profile.ProjectID != 0 {
+		p.value, p.set = profile.ProjectID, true
+	}
+	return nil
+}
+
+// requireProject returns the project ID from --project or the profile, or an
+// error if neither sets one
+func (a *app) requireProject(p *optionalUint64) (uint64, error) {
+	if err := a.defaultProject(p); err != nil {
+		return 0, err
+	}
+	if !p.set {
+		return 0, fmt.Errorf("--project is required")
+	}
+	return p.value, nil
+}
+
+// parseID parses a positional ID argument
+func parseID(kind, s string) (uint64, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s ID %q", kind, s)
+	}
+	return id, nil
+}
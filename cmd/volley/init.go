@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func initCommand() *command {
+	return &command{
+		name:    "init",
+		summary: "Create a source, destination and connection in one step",
+		run:     initPipeline,
+	}
+}
+
+// initResult is the output of volley init
+type initResult struct {
+	Source       volley.Source      `json:"source"`
+	Destination  volley.Destination `json:"destination"`
+	Connection   volley.Connection  `json:"connection"`
+	IngestionURL string             `json:"ingestion_url"`
+	// The source's generated password or API key, shown only once
+	AuthPassword string `json:"auth_password,omitempty"`
+	AuthKey      string `json:"auth_key,omitempty"`
+}
+
+func initPipeline(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	sourceName := fs.String("source", "", "source name")
+	authType := fs.String("auth-type", "", "source authentication type: none, basic or api_key (default none)")
+	username := fs.String("username", "", "basic auth username, with --auth-type basic")
+	keyName := fs.String("key-name", "", "header carrying the API key, with --auth-type api_key (default X-Api-Key)")
+	destURL := fs.String("url", "", "destination URL")
+	destName := fs.String("destination", "", "destination name (default: the URL's host)")
+	var eps, maxRetries optionalInt
+	fs.Var(&eps, "eps", "events per second for the source, destination and connection (default 10)")
+	fs.Var(&maxRetries, "max-retries", "maximum delivery retries (default 3)")
+	noInput := fs.Bool("no-input", false, "fail instead of prompting for missing values")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	if err := a.defaultProject(project); err != nil {
+		return err
+	}
+	p := &prompter{in: bufio.NewReader(a.stdin), out: a.stderr, noInput: *noInput}
+	projectID, err := p.askUint(project, "project", "Project ID")
+	if err != nil {
+		return err
+	}
+	if err := p.ask(sourceName, "source", "Source name", "", nil); err != nil {
+		return err
+	}
+	if err := p.ask(authType, "auth-type", "Source auth type (none, basic, api_key)", "none", validAuthType); err != nil {
+		return err
+	}
+	switch *authType {
+	case "basic":
+		err = p.ask(username, "username", "Basic auth username", "", nil)
+	case "api_key":
+		err = p.ask(keyName, "key-name", "API key header", "X-Api-Key", nil)
+	}
+	if err != nil {
+		return err
+	}
+	if err := p.ask(destURL, "url", "Destination URL", "", validDestinationURL); err != nil {
+		return err
+	}
+	if err := p.ask(destName, "destination", "Destination name", urlHost(*destURL), nil); err != nil {
+		return err
+	}
+	rate, err := p.askInt(&eps, "eps", "Events per second", 10)
+	if err != nil {
+		return err
+	}
+	retries, err := p.askInt(&maxRetries, "max-retries", "Maximum delivery retries", 3)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	var source *volley.Source
+	var dest *volley.Destination
+	var conn *volley.Connection
+	var creds *volley.SourceCredentials
+	err = client.WithTx(func(tx *volley.Tx) error {
+		var err error
+		source, err = tx.CreateSource(projectID, volley.CreateSourceRequest{
+			Name:     *sourceName,
+			EPS:      rate,
+			AuthType: *authType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create source: %w", err)
+		}
+		// Generate the source's credentials; failing here rolls the source back
+		switch *authType {
+		case "basic":
+			creds, err = client.Sources.SetBasicAuth(source.ID, *username, "")
+		case "api_key":
+			creds, err = client.Sources.SetAPIKey(source.ID, *keyName, "")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set source credentials: %w", err)
+		}
+		if creds != nil {
+			source = &creds.Source
+		}
+		dest, err = tx.CreateDestination(projectID, volley.CreateDestinationRequest{
+			Name: *destName,
+			URL:  *destURL,
+			EPS:  rate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create destination: %w", err)
+		}
+		conn, err = tx.CreateConnection(projectID, volley.CreateConnectionRequest{
+			SourceID:      source.ID,
+			DestinationID: dest.ID,
+			Status:        "enabled",
+			EPS:           rate,
+			MaxRetries:    retries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create connection: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result := initResult{
+		Source:       *source,
+		Destination:  *dest,
+		Connection:   *conn,
+		IngestionURL: client.Ingestion.URL(source.IngestionID),
+	}
+	if creds != nil {
+		result.AuthPassword, result.AuthKey = string(creds.Password), string(creds.Key)
+	}
+	t := &table{headers: []string{"SOURCE", "DESTINATION", "CONNECTION", "INGESTION URL"}}
+	t.add(fmt.Sprintf("%s (%d)", source.Slug, source.ID), fmt.Sprintf("%s (%d)", dest.Name, dest.ID),
+		formatID(conn.ID), result.IngestionURL)
+	if secret := result.AuthPassword + result.AuthKey; secret != "" {
+		t.headers = append(t.headers, "GENERATED SECRET")
+		t.rows[0] = append(t.rows[0], secret)
+	}
+	if err := a.render(result, t); err != nil {
+		return err
+	}
+	if creds != nil {
+		fmt.Fprintln(a.stderr, "Store the generated secret now; it cannot be shown again.")
+	}
+	if a.globals.output == "table" {
+		fmt.Fprintf(a.stderr, "\nSend a test webhook with:\n  curl -X POST %s -H 'Content-Type: application/json' -d '{}'\n", result.IngestionURL)
+	}
+	return nil
+}
+
+// prompter asks for values that were not given as flags
+type prompter struct {
+	in      *bufio.Reader
+	out     io.Writer
+	noInput bool
+}
+
+// ask prompts for *value unless it is already set. An empty answer selects
+// def; valid, if not nil, rejects answers until a valid one is given.
+func (p *prompter) ask(value *string, flagName, label, def string, valid func(string) error) error {
+	if *value != "" {
+		if valid != nil {
+			return valid(*value)
+		}
+		return nil
+	}
+	if p.noInput {
+		if def == "" {
+			return fmt.Errorf("--%s is required", flagName)
+		}
+		*value = def
+		return nil
+	}
+
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+		line, readErr := p.in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+
+		var err error
+		if answer == "" {
+			err = fmt.Errorf("--%s is required", flagName)
+		} else if valid != nil {
+			err = valid(answer)
+		}
+		if err == nil {
+			*value = answer
+			return nil
+		}
+		if readErr != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, "  %v\n", err)
+	}
+}
+
+func (p *prompter) askInt(value *optionalInt, flagName, label string, def int) (int, error) {
+	s := ""
+	if value.set {
+		s = strconv.Itoa(value.value)
+	}
+	if err := p.ask(&s, flagName, label, strconv.Itoa(def), validCount); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(s)
+}
+
+func (p *prompter) askUint(value *optionalUint64, flagName, label string) (uint64, error) {
+	s := ""
+	if value.set {
+		s = strconv.FormatUint(value.value, 10)
+	}
+	valid := func(s string) error {
+		_, err := parseID(flagName, s)
+		return err
+	}
+	if err := p.ask(&s, flagName, label, "", valid); err != nil {
+		return 0, err
+	}
+	return parseID(flagName, s)
+}
+
+func validAuthType(s string) error {
+	switch s {
+	case "none", "basic", "api_key":
+		return nil
+	}
+	return fmt.Errorf("auth type must be none, basic or api_key")
+}
+
+func validDestinationURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("destination URL must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func validCount(s string) error {
+	if n, err := strconv.Atoi(s); err != nil || n < 0 {
+		return fmt.Errorf("%q is not a non-negative number", s)
+	}
+	return nil
+}
+
+func urlHost(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func TestInitPrompts(t *testing.T) {
+	emu := emulator.New(nil)
+	defer emu.Close()
+	server := httptest.NewServer(emu)
+	defer server.Close()
+
+	// Project, source, auth type (default), URL, destination name (default), EPS, retries (default)
+	input := "1\nstripe\n\nhttp://localhost:3000/hooks\n\n5\n\n"
+	code, stdout, stderr := runCLIInput(t, server, input, "init")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "Destination name [localhost]") {
+		t.Errorf("Expected the destination name to default to the URL host, got:\n%s", stderr)
+	}
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	sources, _ := client.ListSources(1)
+	conns, _ := client.GetConnections(1)
+	if len(sources) != 1 || len(conns) != 1 {
+		t.Fatalf("Expected one source and one connection, got %d and %d", len(sources), len(conns))
+	}
+	if conns[0].EPS != 5 || conns[0].MaxRetries != 3 || sources[0].EPS != 5 {
+		t.Errorf("Expected EPS 5 and 3 retries, got %+v and %+v", sources[0], conns[0])
+	}
+	if want := client.IngestionURL(sources[0].IngestionID); !strings.Contains(stdout, want) {
+		t.Errorf("Expected output to contain ingestion URL %s, got:\n%s", want, stdout)
+	}
+}
+
+func TestInitSourceCredentials(t *testing.T) {
+	emu := emulator.New(nil)
+	defer emu.Close()
+	server := httptest.NewServer(emu)
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, server, "init", "--no-input", "--project", "1", "--source", "github",
+		"--auth-type", "basic", "--username", "hooks", "--url", "https://api.example.com/hooks", "--eps", "20", "-o", "json")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	var result struct {
+		Source       volley.Source      `json:"source"`
+		Destination  volley.Destination `json:"destination"`
+		AuthPassword string             `json:"auth_password"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if result.AuthPassword == "" || result.Source.AuthType != "basic" || result.Source.AuthUsername != "hooks" {
+		t.Errorf("Expected a generated basic auth password, got %+v", result)
+	}
+	if result.Source.EPS != 20 || result.Destination.EPS != 20 {
+		t.Errorf("Expected --eps to apply to the source and destination, got %d and %d", result.Source.EPS, result.Destination.EPS)
+	}
+	if !strings.Contains(stderr, "cannot be shown again") {
+		t.Errorf("Expected a warning that the secret is shown once, got:\n%s", stderr)
+	}
+
+	// Basic auth needs a username
+	code, _, stderr = runCLI(t, server, "init", "--no-input", "--project", "1", "--source", "gitlab",
+		"--auth-type", "basic", "--url", "https://api.example.com/hooks")
+	if code != 1 || !strings.Contains(stderr, "--username is required") {
+		t.Errorf("Expected a missing username to be rejected, got %d: %s", code, stderr)
+	}
+}
+
+func TestInitRollback(t *testing.T) {
+	emu := emulator.New(nil)
+	defer emu.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/projects/1/connections" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"internal error"}`))
+			return
+		}
+		emu.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	code, _, stderr := runCLI(t, server, "init", "--no-input", "--project", "1",
+		"--source", "stripe", "--url", "https://api.example.com/hooks")
+	if code != 1 {
+		t.Fatalf("Expected exit code 1, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "failed to create connection") || !strings.Contains(stderr, "rolled back destination 1, source 1") {
+		t.Errorf("Expected connection failure and rollback messages, got:\n%s", stderr)
+	}
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	sources, _ := client.ListSources(1)
+	dests, _ := client.ListDestinations(1)
+	if len(sources) != 0 || len(dests) != 0 {
+		t.Errorf("Expected rollback to delete the source and destination, got %d and %d", len(sources), len(dests))
+	}
+}
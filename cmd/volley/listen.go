@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// hopHeaders are not copied from the original request when forwarding
+var hopHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Accept-Encoding":   true,
+	"Transfer-Encoding": true,
+	"Keep-Alive":        true,
+	"Upgrade":           true,
+}
+
+func listenCommand() *command {
+	return &command{
+		name:    "listen",
+		summary: "Forward new project events to a local URL",
+		run:     listen,
+	}
+}
+
+func listen(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	target := fs.String("forward", "", "local URL to forward events to, e.g. http://localhost:8080/hooks (required)")
+	sources := fs.String("source", "", "comma-separated source IDs or slugs to forward (default: all)")
+	interval := fs.Duration("interval", 2*time.Second, "polling interval")
+	timeout := fs.Duration("timeout", 10*time.Second, "local request timeout")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	if *target == "" {
+		return fmt.Errorf("--forward is required")
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	names := loadSourceNames(client, projectID)
+	w := &eventWatcher{client: client, projectID: projectID, interval: *interval, onError: func(err error) {
+		fmt.Fprintf(a.stderr, "poll failed: %v\n", err)
+	}}
+
+	allowed := make(map[uint64]bool)
+	if *sources != "" {
+		for _, s := range strings.Split(*sources, ",") {
+			id, ok := names.resolve(strings.TrimSpace(s))
+			if !ok {
+				return fmt.Errorf("unknown source %q", s)
+			}
+			allowed[id] = true
+		}
+		if len(allowed) == 1 {
+			for id := range allowed {
+				id := id
+				w.filter.SourceID = &id
+			}
+		}
+	}
+
+	f := &forwarder{
+		target: *target,
+		http:   &http.Client{Timeout: *timeout},
+		out:    a.stdout,
+		names:  names,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(a.stderr, "Forwarding events for project %d to %s\n", projectID, *target)
+	fmt.Fprintf(a.stderr, "Commands: l (list failed), r N (retry), r all, q (quit)\n")
+	go f.prompt(a.stdin, stop)
+
+	return w.run(ctx, func(e volley.Event) error {
+		if len(allowed) > 0 && !allowed[e.SourceID] {
+			return nil
+		}
+		f.forward(e)
+		return nil
+	})
+}
+
+// forwarder replays events against a local URL and keeps failed deliveries for retry
+type forwarder struct {
+	target string
+	http   *http.Client
+	names  sourceNames
+
+	mu     sync.Mutex
+	out    io.Writer
+	failed []volley.Event
+}
+
+// forward POSTs an event's raw body and original headers to the target and
+// reports whether the local service accepted it
+func (f *forwarder) forward(e volley.Event) bool {
+	ok, summary := f.deliver(e)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	marker := "->"
+	if !ok {
+		f.failed = append(f.failed, e)
+		marker = fmt.Sprintf("!! [%d]", len(f.failed))
+	}
+	fmt.Fprintf(f.out, "%s %s  %s  %s\n", marker, e.EventID, f.names.name(e.SourceID), summary)
+	return ok
+}
+
+func (f *forwarder) deliver(e volley.Event) (bool, string) {
+	req, err := http.NewRequest(http.MethodPost, f.target, bytes.NewBufferString(e.RawBody))
+	if err != nil {
+		return false, err.Error()
+	}
+	for name, value := range e.Headers {
+		if hopHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			req.Header.Set(name, v)
+		case []interface{}:
+			for _, item := range v {
+				req.Header.Add(name, fmt.Sprint(item))
+			}
+		default:
+			req.Header.Set(name, fmt.Sprint(v))
+		}
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Volley-Event-ID", e.EventID)
+
+	start := time.Now()
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return false, err.Error()
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	return resp.StatusCode < 300, fmt.Sprintf("%s (%s)", resp.Status, elapsed)
+}
+
+// prompt reads interactive commands until stdin is closed or the user quits
+func (f *forwarder) prompt(in io.Reader, quit func()) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if f.handle(strings.TrimSpace(scanner.Text())) {
+			quit()
+			return
+		}
+	}
+}
+
+// handle runs a single interactive command and reports whether to quit
+func (f *forwarder) handle(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "q", "quit", "exit":
+		return true
+	case "l", "list":
+		f.mu.Lock()
+		if len(f.failed) == 0 {
+			fmt.Fprintln(f.out, "No failed deliveries")
+		}
+		for i, e := range f.failed {
+			fmt.Fprintf(f.out, "[%d] %s  %s  %s\n", i+1, e.EventID, f.names.name(e.SourceID), formatTime(e.CreatedAt))
+		}
+		f.mu.Unlock()
+	case "r", "retry":
+		if len(fields) < 2 {
+			fmt.Fprintln(f.out, "Usage: r N | r all")
+			return false
+		}
+		f.retry(fields[1])
+	default:
+		fmt.Fprintln(f.out, "Commands: l (list failed), r N (retry), r all, q (quit)")
+	}
+	return false
+}
+
+// retry re-forwards one failed delivery by its list number, or all of them
+func (f *forwarder) retry(which string) {
+	f.mu.Lock()
+	var pending []volley.Event
+	if which == "all" {
+		pending, f.failed = f.failed, nil
+	} else {
+		n, err := strconv.Atoi(which)
+		if err != nil || n < 1 || n > len(f.failed) {
+			fmt.Fprintf(f.out, "No failed delivery %q\n", which)
+			f.mu.Unlock()
+			return
+		}
+		pending = []volley.Event{f.failed[n-1]}
+		f.failed = append(f.failed[:n-1], f.failed[n:]...)
+	}
+	f.mu.Unlock()
+
+	for _, e := range pending {
+		f.forward(e)
+	}
+}
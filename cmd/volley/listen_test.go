@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func TestForwarderForwardAndRetry(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	status := http.StatusInternalServerError
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Stripe-Signature") != "t=1,v1=abc" {
+			t.Errorf("Expected original Stripe-Signature header, got %q", r.Header.Get("Stripe-Signature"))
+		}
+		if r.Header.Get("X-Volley-Event-ID") != "evt_1" {
+			t.Errorf("Expected X-Volley-Event-ID evt_1, got %q", r.Header.Get("X-Volley-Event-ID"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	defer local.Close()
+
+	var out bytes.Buffer
+	f := &forwarder{target: local.URL, http: local.Client(), out: &out, names: sourceNames{1: "stripe"}}
+
+	event := volley.Event{
+		ID:       1,
+		EventID:  "evt_1",
+		SourceID: 1,
+		RawBody:  `{"type":"charge.succeeded"}`,
+		Headers: map[string]interface{}{
+			"Stripe-Signature": "t=1,v1=abc",
+			"Host":             "api.volleyhooks.com",
+		},
+	}
+
+	if f.forward(event) {
+		t.Fatal("Expected forward to fail on a 500 response")
+	}
+	if len(f.failed) != 1 {
+		t.Fatalf("Expected 1 failed delivery, got %d", len(f.failed))
+	}
+	if !strings.Contains(out.String(), "500 Internal Server Error") {
+		t.Errorf("Expected local status in output, got %q", out.String())
+	}
+
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+
+	if f.handle("r 1") {
+		t.Fatal("retry should not quit")
+	}
+	if len(f.failed) != 0 {
+		t.Errorf("Expected no failed deliveries after retry, got %d", len(f.failed))
+	}
+	if len(bodies) != 2 || bodies[1] != event.RawBody {
+		t.Errorf("Expected raw body to be forwarded twice, got %v", bodies)
+	}
+
+	if !f.handle("q") {
+		t.Error("Expected q to quit")
+	}
+}
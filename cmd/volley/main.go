@@ -0,0 +1,253 @@
+// Command volley is a command-line interface to the Volley API built on the Go SDK.
+//
+// Usage:
+//
+//	volley [--profile NAME] [--org ID] [--output table|json|yaml] <command> [subcommand] [flags]
+//
+// Settings come from the selected profile in ~/.config/volley/config.yaml,
+// overlaid by VOLLEY_* environment variables (such as VOLLEY_API_TOKEN) and
+// then by flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command is a node in the command tree. Groups have subcommands, leaves have run.
+type command struct {
+	name        string
+	args        string
+	summary     string
+	run         func(a *app, args []string) error
+	subcommands []*command
+}
+
+// commands returns the top-level command tree
+func commands() []*command {
+	return []*command{
+		orgsCommand(),
+		tokensCommand(),
+		projectsCommand(),
+		sourcesCommand(),
+		destinationsCommand(),
+		connectionsCommand(),
+		rulesCommand(),
+		eventsCommand(),
+		attemptsCommand(),
+		replayCommand(),
+		initCommand(),
+		listenCommand(),
+		emulateCommand(),
+	}
+}
+
+var (
+	// errUsage is returned when a command is invoked incorrectly; usage has already been printed
+	errUsage = errors.New("usage")
+	// errHelp is returned when help was requested and printed
+	errHelp = errors.New("help")
+)
+
+// run executes the CLI and returns the process exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	a := &app{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		globals: globalFlags{
+			output: "table",
+		},
+	}
+
+	fs := flag.NewFlagSet("volley", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	a.globals.register(fs)
+	fs.Usage = func() { a.usage(nil, commands()) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if err := a.dispatch(nil, commands(), fs.Args()); err != nil {
+		if errors.Is(err, errHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// app holds the state shared by every command
+type app struct {
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	globals globalFlags
+	client  *volley.Client
+	profile *volley.Profile
+	// cmd and path identify the running leaf command, e.g. "sources list"
+	cmd  *command
+	path string
+}
+
+// globalFlags are accepted before the command and by every subcommand
+type globalFlags struct {
+	profile string
+	org     uint64
+	output  string
+	baseURL string
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.profile, "profile", g.profile, "config profile to use (default $VOLLEY_PROFILE or the config's default)")
+	fs.Uint64Var(&g.org, "org", g.org, "organization ID to use for requests")
+	fs.StringVar(&g.output, "output", g.output, "output format: table, json or yaml")
+	fs.StringVar(&g.output, "o", g.output, "shorthand for --output")
+	fs.StringVar(&g.baseURL, "base-url", g.baseURL, "Volley API base URL (default from the profile or "+volley.DefaultBaseURL+")")
+}
+
+// dispatch walks the command tree and runs the selected leaf
+func (a *app) dispatch(path []string, cmds []*command, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage(path, cmds)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	for _, cmd := range cmds {
+		if cmd.name != args[0] {
+			continue
+		}
+		if cmd.run != nil {
+			a.cmd = cmd
+			a.path = strings.Join(append(path, cmd.name), " ")
+			return cmd.run(a, args[1:])
+		}
+		return a.dispatch(append(path, cmd.name), cmd.subcommands, args[1:])
+	}
+
+	fmt.Fprintf(a.stderr, "Unknown command %q\n\n", strings.Join(append(path, args[0]), " "))
+	a.usage(path, cmds)
+	return errUsage
+}
+
+func (a *app) usage(path []string, cmds []*command) {
+	prefix := strings.Join(append([]string{"volley"}, path...), " ")
+	fmt.Fprintf(a.stderr, "Usage: %s <command> [flags]\n\nCommands:\n", prefix)
+	tw := tabwriter.NewWriter(a.stderr, 0, 4, 2, ' ', 0)
+	for _, cmd := range cmds {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	fmt.Fprintf(tw, "\nGlobal flags:\n")
+	fmt.Fprintf(tw, "  --profile NAME\tconfig profile to use\n")
+	fmt.Fprintf(tw, "  --org ID\torganization ID to use for requests\n")
+	fmt.Fprintf(tw, "  -o, --output FORMAT\toutput format: table, json or yaml\n")
+	fmt.Fprintf(tw, "  --base-url URL\tVolley API base URL\n")
+	tw.Flush()
+}
+
+// flags returns a FlagSet for the running command with the global flags registered
+func (a *app) flags() *flag.FlagSet {
+	cmd := a.cmd
+	fs := flag.NewFlagSet(a.path, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.globals.register(fs)
+	fs.Usage = func() {
+		usage := a.path
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+		fmt.Fprintf(a.stderr, "Usage: volley %s [flags]\n\n%s\n\nFlags:\n", usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses flags that may be interleaved with positional arguments and
+// checks the number of positional arguments
+func (a *app) parse(fs *flag.FlagSet, args []string, positional int) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, errHelp
+			}
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(rest) != positional {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return rest, nil
+}
+
+// api returns the API client, creating it on first use
+func (a *app) api() (*volley.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	profile, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []volley.ClientOption
+	if a.globals.baseURL != "" {
+		opts = append(opts, volley.WithBaseURL(a.globals.baseURL))
+	}
+	if a.globals.org != 0 {
+		opts = append(opts, volley.WithOrganizationID(a.globals.org))
+	}
+	client, err := profile.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+	return a.client, nil
+}
+
+// config returns the selected profile, loading the config on first use
+func (a *app) config() (*volley.Profile, error) {
+	if a.profile != nil {
+		return a.profile, nil
+	}
+
+	cfg, err := volley.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cfg.Profile(a.globals.profile)
+	if err != nil {
+		return nil, err
+	}
+	a.profile = profile
+	return a.profile, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runCLI runs the CLI against a test server and returns exit code, stdout and stderr
+func runCLI(t *testing.T, server *httptest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	return runCLIInput(t, server, "", args...)
+}
+
+// runCLIInput is runCLI with input on stdin
+func runCLIInput(t *testing.T, server *httptest.Server, input string, args ...string) (int, string, string) {
+	t.Helper()
+	t.Setenv("VOLLEY_API_TOKEN", "test-token")
+	t.Setenv("VOLLEY_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--base-url", server.URL}, args...)
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func sourcesServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/projects/1/sources" {
+			t.Errorf("Expected path /api/projects/1/sources, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("X-Organization-ID"); got != "7" {
+			t.Errorf("Expected X-Organization-ID 7, got %q", got)
+		}
+
+		response := map[string]interface{}{
+			"sources": []map[string]interface{}{
+				{
+					"id":           1,
+					"slug":         "stripe-webhooks",
+					"ingestion_id": "src_abc123",
+					"eps":          10,
+					"status":       "active",
+					"auth_type":    "none",
+					"created_at":   time.Now().Format(time.RFC3339),
+					"updated_at":   time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestSourcesListOutputFormats(t *testing.T) {
+	server := sourcesServer(t)
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, server, "--org", "7", "sources", "list", "--project", "1")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "ID") || !strings.Contains(stdout, "stripe-webhooks") {
+		t.Errorf("Unexpected table output:\n%s", stdout)
+	}
+
+	// Global flags are also accepted after the subcommand
+	code, stdout, stderr = runCLI(t, server, "sources", "list", "--project", "1", "--org", "7", "-o", "json")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	var sources []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &sources); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+	if len(sources) != 1 || sources[0]["ingestion_id"] != "src_abc123" {
+		t.Errorf("Unexpected JSON output: %v", sources)
+	}
+
+	code, stdout, _ = runCLI(t, server, "--org", "7", "-o", "yaml", "sources", "list", "--project", "1")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if !strings.HasPrefix(stdout, "- id: 1\n  slug: stripe-webhooks\n") {
+		t.Errorf("Unexpected YAML output:\n%s", stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"nope"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for unknown command, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `Unknown command "nope"`) {
+		t.Errorf("Expected unknown command message, got %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"sources", "get"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for missing argument, got %d", code)
+	}
+
+	stderr.Reset()
+	t.Setenv("VOLLEY_API_TOKEN", "")
+	t.Setenv("VOLLEY_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	if code := run([]string{"orgs", "list"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 without a token, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "VOLLEY_API_TOKEN") {
+		t.Errorf("Expected token error, got %q", stderr.String())
+	}
+}
+
+func TestProfileConfig(t *testing.T) {
+	server := sourcesServer(t)
+	defer server.Close()
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	data := "profiles:\n  staging:\n    token: profile-token\n    base_url: " + server.URL + "\n    org_id: 7\n    project_id: 1\n"
+	if err := os.WriteFile(config, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VOLLEY_CONFIG", config)
+	t.Setenv("VOLLEY_API_TOKEN", "")
+
+	// The profile supplies the token, base URL, organization and project
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--profile", "staging", "sources", "list"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "stripe-webhooks") {
+		t.Errorf("Unexpected output:\n%s", stdout.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"--profile", "prod", "sources", "list"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for an unknown profile, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `profile "prod" not found`) {
+		t.Errorf("Expected a missing profile error, got %q", stderr.String())
+	}
+}
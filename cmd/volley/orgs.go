@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func orgsCommand() *command {
+	return &command{
+		name:    "orgs",
+		summary: "Manage organizations",
+		subcommands: []*command{
+			{name: "list", summary: "List organizations you have access to", run: orgsList},
+			{name: "get", summary: "Show the current organization (or --org)", run: orgsGet},
+			{name: "create", args: "<name>", summary: "Create an organization", run: orgsCreate},
+			{name: "update", summary: "Rename the current organization (or --org)", run: orgsUpdate},
+			{name: "delete", args: "<id>", summary: "Delete an organization and all of its projects", run: orgsDelete},
+			{name: "leave", args: "<id>", summary: "Leave an organization", run: orgsLeave},
+			{
+				name:    "members",
+				summary: "Manage the members of the current organization (or --org)",
+				subcommands: []*command{
+					{name: "list", summary: "List members", run: membersList},
+					{name: "set-role", args: "<member-id> <role>", summary: "Change a member's role", run: membersSetRole},
+					{name: "remove", args: "<member-id>", summary: "Remove a member", run: membersRemove},
+				},
+			},
+			{
+				name:    "invites",
+				summary: "Manage the invites of the current organization (or --org)",
+				subcommands: []*command{
+					{name: "list", summary: "List pending invites", run: invitesList},
+					{name: "create", args: "<email>", summary: "Invite someone by email", run: invitesCreate},
+					{name: "revoke", args: "<invite-id>", summary: "Revoke a pending invite", run: invitesRevoke},
+				},
+			},
+		},
+	}
+}
+
+func orgTable(orgs ...volley.Organization) *table {
+	t := &table{headers: []string{"ID", "NAME", "SLUG", "ROLE", "CREATED"}}
+	for _, o := range orgs {
+		t.add(formatID(o.ID), o.Name, o.Slug, o.Role, formatTime(o.CreatedAt))
+	}
+	return t
+}
+
+func memberTable(members ...volley.Member) *table {
+	t := &table{headers: []string{"ID", "USER", "EMAIL", "ROLE", "JOINED"}}
+	for _, m := range members {
+		t.add(formatID(m.ID), formatID(m.UserID), m.Email, string(m.Role), formatTime(m.CreatedAt))
+	}
+	return t
+}
+
+func inviteTable(invites ...volley.Invite) *table {
+	t := &table{headers: []string{"ID", "EMAIL", "ROLE", "EXPIRES", "CREATED"}}
+	for _, inv := range invites {
+		t.add(formatID(inv.ID), inv.Email, string(inv.Role), formatTime(inv.ExpiresAt), formatTime(inv.CreatedAt))
+	}
+	return t
+}
+
+// parseRole parses a role argument
+func parseRole(s string) (volley.Role, error) {
+	role := volley.Role(s)
+	if !role.Valid() {
+		return "", fmt.Errorf("invalid role %q: use owner, admin, member or viewer", s)
+	}
+	return role, nil
+}
+
+// currentOrg returns the ID from --org, or the current organization's
+func currentOrg(a *app, client *volley.Client) (uint64, error) {
+	if a.globals.org != 0 {
+		return a.globals.org, nil
+	}
+	org, err := client.Organizations.Current()
+	if err != nil {
+		return 0, err
+	}
+	return org.ID, nil
+}
+
+func orgsList(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	orgs, err := client.Organizations.List()
+	if err != nil {
+		return err
+	}
+	return a.render(orgs, orgTable(orgs...))
+}
+
+func orgsGet(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	org, err := client.Organizations.Current()
+	if err != nil {
+		return err
+	}
+	return a.render(org, orgTable(*org))
+}
+
+func orgsCreate(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	org, err := client.Organizations.Create(volley.CreateOrganizationRequest{Name: pos[0]})
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return a.render(org, orgTable(*org))
+}
+
+func orgsUpdate(a *app, args []string) error {
+	fs := a.flags()
+	name := fs.String("name", "", "new organization name (required)")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	orgID, err := currentOrg(a, client)
+	if err != nil {
+		return err
+	}
+
+	org, err := client.Organizations.Update(orgID, volley.UpdateOrganizationRequest{Name: *name})
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return a.render(org, orgTable(*org))
+}
+
+func orgsDelete(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("organization", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Organizations.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Deleted organization %d\n", id)
+	return nil
+}
+
+func orgsLeave(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("organization", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Organizations.Leave(id); err != nil {
+		return fmt.Errorf("failed to leave organization: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Left organization %d\n", id)
+	return nil
+}
+
+func membersList(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	orgID, err := currentOrg(a, client)
+	if err != nil {
+		return err
+	}
+
+	members, err := client.Organizations.ListMembers(orgID)
+	if err != nil {
+		return err
+	}
+	return a.render(members, memberTable(members...))
+}
+
+func membersSetRole(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("member", pos[0])
+	if err != nil {
+		return err
+	}
+	role, err := parseRole(pos[1])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	member, err := client.Organizations.UpdateMemberRole(id, role)
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+	return a.render(member, memberTable(*member))
+}
+
+func membersRemove(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("member", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Organizations.RemoveMember(id); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Removed member %d\n", id)
+	return nil
+}
+
+func invitesList(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	orgID, err := currentOrg(a, client)
+	if err != nil {
+		return err
+	}
+
+	invites, err := client.Organizations.ListInvites(orgID)
+	if err != nil {
+		return err
+	}
+	return a.render(invites, inviteTable(invites...))
+}
+
+func invitesCreate(a *app, args []string) error {
+	fs := a.flags()
+	roleName := fs.String("role", string(volley.RoleMember), "role to grant: owner, admin, member or viewer")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	role, err := parseRole(*roleName)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	orgID, err := currentOrg(a, client)
+	if err != nil {
+		return err
+	}
+
+	invite, err := client.Organizations.InviteMember(orgID, volley.InviteMemberRequest{Email: pos[0], Role: role})
+	if err != nil {
+		return fmt.Errorf("failed to invite member: %w", err)
+	}
+	return a.render(invite, inviteTable(*invite))
+}
+
+func invitesRevoke(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("invite", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Organizations.RevokeInvite(id); err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Revoked invite %d\n", id)
+	return nil
+}
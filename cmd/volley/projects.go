@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func projectsCommand() *command {
+	return &command{
+		name:    "projects",
+		summary: "Manage projects",
+		subcommands: []*command{
+			{name: "list", summary: "List projects in the organization", run: projectsList},
+			{name: "create", args: "<name>", summary: "Create a project", run: projectsCreate},
+			{name: "update", args: "<project-id>", summary: "Rename a project", run: projectsUpdate},
+			{name: "delete", args: "<project-id>", summary: "Delete a project", run: projectsDelete},
+		},
+	}
+}
+
+func projectTable(projects ...volley.Project) *table {
+	t := &table{headers: []string{"ID", "NAME", "DEFAULT", "CREATED"}}
+	for _, p := range projects {
+		t.add(formatID(p.ID), p.Name, formatBool(p.IsDefault), formatTime(p.CreatedAt))
+	}
+	return t
+}
+
+func projectsList(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	projects, err := client.Projects.List()
+	if err != nil {
+		return err
+	}
+	return a.render(projects, projectTable(projects...))
+}
+
+func projectsCreate(a *app, args []string) error {
+	fs := a.flags()
+	isDefault := fs.Bool("default", false, "make this the organization's default project")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	project, err := client.Projects.Create(volley.CreateProjectRequest{Name: pos[0], IsDefault: *isDefault})
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	return a.render(project, projectTable(*project))
+}
+
+func projectsUpdate(a *app, args []string) error {
+	fs := a.flags()
+	name := fs.String("name", "", "new project name (required)")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("project", pos[0])
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	project, err := client.Projects.Update(id, volley.UpdateProjectRequest{Name: *name})
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	return a.render(project, projectTable(*project))
+}
+
+func projectsDelete(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("project", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Projects.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Deleted project %d\n", id)
+	return nil
+}
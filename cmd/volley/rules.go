@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/rules"
+)
+
+// maxDetailWidth is the widest DETAIL column of "rules test" tables
+const maxDetailWidth = 80
+
+func rulesCommand() *command {
+	return &command{
+		name:    "rules",
+		summary: "Try connection filters and transforms",
+		subcommands: []*command{
+			{name: "test", summary: "Evaluate filters and transforms against events", run: rulesTest},
+		},
+	}
+}
+
+func ruleResultTable(results ...rules.Result) *table {
+	t := &table{headers: []string{"EVENT ID", "RESULT", "DETAIL"}}
+	for _, res := range results {
+		result, detail := "match", res.Body
+		switch {
+		case res.Error != "":
+			result, detail = "error", res.Error
+		case !res.Matched:
+			result, detail = "no match", res.Reason
+		}
+		t.add(res.EventID, result, orDash(truncate(detail, maxDetailWidth)))
+	}
+	return t
+}
+
+// truncate shortens s to at most n runes, marking it with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
+
+func rulesTest(a *app, args []string) error {
+	fs := a.flags()
+	rulesFile := fs.String("rules", "", "YAML or JSON rules file")
+	var connection optionalUint64
+	fs.Var(&connection, "connection", "test the rules of an existing connection")
+	eventsFile := fs.String("events", "", "events saved with \"volley events list -o json\" (default: fetch from the API)")
+	project := projectFlag(fs)
+	var source optionalUint64
+	var start, end optionalTime
+	fs.Var(&source, "source", "only events from this source ID")
+	status := fs.String("status", "", "only events with this status")
+	fs.Var(&start, "since", "only events after this time (RFC 3339 or a duration such as 1h)")
+	fs.Var(&end, "until", "only events before this time (RFC 3339 or a duration such as 1h)")
+	limit := fs.Int("limit", 100, "maximum number of events to fetch")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if (*rulesFile == "") == !connection.set {
+		return fmt.Errorf("exactly one of --rules and --connection is required")
+	}
+
+	var client *volley.Client
+	if connection.set || *eventsFile == "" {
+		var err error
+		if client, err = a.api(); err != nil {
+			return err
+		}
+	}
+
+	var definition volley.ConnectionRules
+	if connection.set {
+		conn, err := client.Connections.Get(connection.value)
+		if err != nil {
+			return err
+		}
+		definition = volley.ConnectionRules{Filters: conn.Filters, Transform: conn.Transform, Retry: conn.Retry}
+		if !source.set {
+			source.value, source.set = conn.SourceID, true
+		}
+	} else {
+		loaded, err := volley.LoadConnectionRulesFile(*rulesFile)
+		if err != nil {
+			return err
+		}
+		definition = *loaded
+	}
+	rule, err := rules.Compile(definition)
+	if err != nil {
+		return err
+	}
+
+	var events []volley.Event
+	if *eventsFile != "" {
+		if events, err = rules.LoadEventsFile(*eventsFile); err != nil {
+			return err
+		}
+	} else {
+		projectID, err := a.requireProject(project)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Events.List(projectID, &volley.ListEventsOptions{
+			SourceID:  source.ptr(),
+			Status:    *status,
+			StartTime: start.ptr(),
+			EndTime:   end.ptr(),
+			Limit:     limit,
+		})
+		if err != nil {
+			return err
+		}
+		events = resp.Requests
+	}
+
+	report := rule.Test(events)
+	if err := a.render(report, ruleResultTable(report.Results...)); err != nil {
+		return err
+	}
+	if a.globals.output == "table" {
+		fmt.Fprintf(a.stderr, "%d of %d events match, %d errors\n", report.Matched, report.Total, report.Errors)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func sourcesCommand() *command {
+	return &command{
+		name:    "sources",
+		summary: "Manage webhook sources",
+		subcommands: []*command{
+			{name: "list", summary: "List sources in a project", run: sourcesList},
+			{name: "get", args: "<source-id>", summary: "Show a source", run: sourcesGet},
+			{name: "create", args: "<name>", summary: "Create a source", run: sourcesCreate},
+			{name: "update", args: "<source-id>", summary: "Update a source", run: sourcesUpdate},
+			{name: "delete", args: "<source-id>", summary: "Delete a source", run: sourcesDelete},
+			{name: "rotate-secret", args: "<source-id>", summary: "Generate a new webhook secret and print it once", run: sourcesRotateSecret},
+			{name: "set-auth", args: "<source-id>", summary: "Set basic or API key credentials, printing generated secrets once", run: sourcesSetAuth},
+		},
+	}
+}
+
+func sourceTable(sources ...volley.Source) *table {
+	t := &table{headers: []string{"ID", "SLUG", "INGESTION ID", "EPS", "AUTH", "STATUS", "CONNECTIONS"}}
+	for _, s := range sources {
+		t.add(formatID(s.ID), s.Slug, s.IngestionID, strconv.Itoa(s.EPS), orDash(s.AuthType), s.Status,
+			strconv.FormatInt(s.ConnectionCount, 10))
+	}
+	return t
+}
+
+func sourcesList(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	sources, err := client.Sources.List(projectID)
+	if err != nil {
+		return err
+	}
+	return a.render(sources, sourceTable(sources...))
+}
+
+func sourcesGet(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("source", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	source, err := client.Sources.Get(id)
+	if err != nil {
+		return err
+	}
+	return a.render(source, sourceTable(*source))
+}
+
+func sourcesCreate(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	eps := fs.Int("eps", 10, "events per second")
+	authType := fs.String("auth-type", "none", "authentication type: none, basic or api_key")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	source, err := client.Sources.Create(projectID, volley.CreateSourceRequest{
+		Name:     pos[0],
+		EPS:      *eps,
+		AuthType: *authType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create source: %w", err)
+	}
+	return a.render(source, sourceTable(*source))
+}
+
+func sourcesUpdate(a *app, args []string) error {
+	fs := a.flags()
+	var eps optionalInt
+	fs.Var(&eps, "eps", "events per second")
+	name := fs.String("name", "", "new source name")
+	authType := fs.String("auth-type", "", "authentication type: none, basic or api_key")
+	status := fs.String("status", "", "source status")
+	var verify optionalBool
+	fs.Var(&verify, "verify-signature", "reject webhooks not signed with the webhook secret")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("source", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	source, err := client.Sources.Update(id, volley.UpdateSourceRequest{
+		Name:            *name,
+		EPS:             eps.ptr(),
+		AuthType:        *authType,
+		Status:          *status,
+		VerifySignature: verify.ptr(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update source: %w", err)
+	}
+	return a.render(source, sourceTable(*source))
+}
+
+func sourcesDelete(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("source", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Sources.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete source: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Deleted source %d\n", id)
+	return nil
+}
+
+func sourcesRotateSecret(a *app, args []string) error {
+	fs := a.flags()
+	grace := fs.Duration("grace", 0, "how long the old secret stays valid, e.g. 24h (default: revoke it immediately)")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("source", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Sources.RotateSecret(id, *grace)
+	if err != nil {
+		return fmt.Errorf("failed to rotate secret: %w", err)
+	}
+	t := &table{headers: []string{"ID", "SLUG", "WEBHOOK SECRET", "PREVIOUS SECRET EXPIRES"}}
+	t.add(formatID(secret.Source.ID), secret.Source.Slug, string(secret.WebhookSecret), formatOptionalTime(secret.PreviousSecretExpiresAt))
+	if err := a.render(secret, t); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stderr, "Store the secret now; it cannot be shown again.")
+	return nil
+}
+
+func sourcesSetAuth(a *app, args []string) error {
+	fs := a.flags()
+	username := fs.String("username", "", "basic auth username")
+	password := fs.String("password", "", "basic auth password (default: generated)")
+	keyName := fs.String("key-name", "", "header carrying the API key")
+	key := fs.String("key", "", "API key (default: generated)")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("source", pos[0])
+	if err != nil {
+		return err
+	}
+	if (*username == "") == (*keyName == "") {
+		return fmt.Errorf("exactly one of --username and --key-name is required")
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	var creds *volley.SourceCredentials
+	if *username != "" {
+		creds, err = client.Sources.SetBasicAuth(id, *username, *password)
+	} else {
+		creds, err = client.Sources.SetAPIKey(id, *keyName, *key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set credentials: %w", err)
+	}
+	t := sourceTable(creds.Source)
+	if secret := creds.Password + creds.Key; secret != "" {
+		t.headers = append(t.headers, "GENERATED SECRET")
+		t.rows[0] = append(t.rows[0], string(secret))
+	}
+	if err := a.render(creds, t); err != nil {
+		return err
+	}
+	if creds.Password != "" || creds.Key != "" {
+		fmt.Fprintln(a.stderr, "Store the generated secret now; it cannot be shown again.")
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorGray   = "\033[90m"
+)
+
+// statusColors maps event and attempt statuses to terminal colors
+var statusColors = map[string]string{
+	"processed": colorGreen,
+	"success":   colorGreen,
+	"pending":   colorYellow,
+	"failed":    colorRed,
+	"dropped":   colorGray,
+}
+
+func eventsTail(a *app, args []string) error {
+	fs := a.flags()
+	project := projectFlag(fs)
+	source := fs.String("source", "", "only events from this source (ID or slug)")
+	status := fs.String("status", "", "only events with this status: processed, pending, failed or dropped")
+	var cursor optionalUint64
+	fs.Var(&cursor, "cursor", "resume after this event (request) ID, as printed on exit")
+	var since optionalTime
+	fs.Var(&since, "since", "also show existing events after this time (RFC 3339 or a duration such as 10m)")
+	body := fs.Bool("body", false, "pretty-print each event's raw body")
+	noColor := fs.Bool("no-color", false, "disable colored output")
+	interval := fs.Duration("interval", 2*time.Second, "polling interval")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	projectID, err := a.requireProject(project)
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	names := loadSourceNames(client, projectID)
+	w := &eventWatcher{
+		client:    client,
+		projectID: projectID,
+		interval:  *interval,
+		cursor:    cursor.value,
+		backlog:   since.set,
+		onError: func(err error) {
+			fmt.Fprintf(a.stderr, "poll failed: %v\n", err)
+		},
+		filter: volley.ListEventsOptions{
+			Status:    *status,
+			StartTime: since.ptr(),
+		},
+	}
+	if *source != "" {
+		id, ok := names.resolve(*source)
+		if !ok {
+			return fmt.Errorf("unknown source %q", *source)
+		}
+		w.filter.SourceID = &id
+	}
+
+	p := &tailPrinter{
+		out:    a.stdout,
+		client: client,
+		names:  names,
+		body:   *body,
+		color:  !*noColor && colorSupported(a.stdout),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(a.stderr, "Tailing events for project %d (Ctrl-C to stop)\n", projectID)
+	err = w.run(ctx, func(e volley.Event) error {
+		p.print(projectID, e)
+		return nil
+	})
+
+	fmt.Fprintf(a.stderr, "\nResume with: volley events tail --project %d --cursor %d\n", projectID, w.cursor)
+	return err
+}
+
+// tailPrinter writes one line per event, optionally followed by its body
+type tailPrinter struct {
+	out    io.Writer
+	client *volley.Client
+	names  sourceNames
+	body   bool
+	color  bool
+}
+
+func (p *tailPrinter) print(projectID uint64, e volley.Event) {
+	code, latency := "-", "-"
+	if at := p.latestAttempt(projectID, e); at != nil {
+		code = fmt.Sprintf("%d", at.StatusCode)
+		latency = fmt.Sprintf("%dms", at.DurationMs)
+	}
+
+	fmt.Fprintf(p.out, "%s  %-24s  %-20s  %s  %4s  %7s\n",
+		e.CreatedAt.Local().Format("15:04:05"),
+		e.EventID,
+		p.names.name(e.SourceID),
+		p.colorize(fmt.Sprintf("%-9s", e.Status), e.Status),
+		code,
+		latency,
+	)
+
+	if p.body && e.RawBody != "" {
+		fmt.Fprintln(p.out, indentBody(e.RawBody))
+	}
+}
+
+// latestAttempt returns the most recent delivery attempt of an event, fetching
+// it when the listing did not include attempts
+func (p *tailPrinter) latestAttempt(projectID uint64, e volley.Event) *volley.DeliveryAttempt {
+	attempts := e.DeliveryAttempts
+	if len(attempts) == 0 {
+		limit := 1
+		resp, err := p.client.DeliveryAttempts.List(projectID, &volley.ListDeliveryAttemptsOptions{
+			EventID: e.EventID,
+			Sort:    "time",
+			Limit:   &limit,
+		})
+		if err != nil || len(resp.Attempts) == 0 {
+			return nil
+		}
+		attempts = resp.Attempts
+	}
+
+	latest := &attempts[0]
+	for i := range attempts {
+		if attempts[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &attempts[i]
+		}
+	}
+	return latest
+}
+
+func (p *tailPrinter) colorize(s, status string) string {
+	color, ok := statusColors[status]
+	if !p.color || !ok {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// indentBody pretty-prints JSON bodies and returns other bodies unchanged
+func indentBody(raw string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "    ", "  "); err != nil {
+		return "    " + raw
+	}
+	return "    " + buf.String()
+}
+
+// colorSupported reports whether w is a terminal and NO_COLOR is not set
+func colorSupported(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
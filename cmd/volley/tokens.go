@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func tokensCommand() *command {
+	return &command{
+		name:    "tokens",
+		summary: "Manage API tokens",
+		subcommands: []*command{
+			{name: "list", summary: "List your API tokens", run: tokensList},
+			{name: "create", args: "<name>", summary: "Create an API token and print its secret once", run: tokensCreate},
+			{name: "revoke", args: "<id>", summary: "Revoke an API token", run: tokensRevoke},
+			{name: "current", summary: "Show the token in use and its scopes", run: tokensCurrent},
+		},
+	}
+}
+
+func tokenTable(tokens ...volley.APIToken) *table {
+	t := &table{headers: []string{"ID", "NAME", "PREFIX", "SCOPES", "EXPIRES", "LAST USED", "CREATED"}}
+	for _, tok := range tokens {
+		t.add(formatID(tok.ID), tok.Name, tok.Prefix, formatScopes(tok.Scopes),
+			formatOptionalTime(tok.ExpiresAt), formatOptionalTime(tok.LastUsedAt), formatTime(tok.CreatedAt))
+	}
+	return t
+}
+
+func formatScopes(scopes []volley.Scope) string {
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ",")
+}
+
+func tokensList(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := client.Tokens.List()
+	if err != nil {
+		return err
+	}
+	return a.render(tokens, tokenTable(tokens...))
+}
+
+func tokensCreate(a *app, args []string) error {
+	fs := a.flags()
+	scopes := fs.String("scopes", "read,write", "comma-separated scopes: read, write, admin")
+	expires := fs.Duration("expires", 0, "lifetime, e.g. 720h (default: never expires)")
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	req := volley.CreateTokenRequest{Name: pos[0]}
+	for _, s := range strings.Split(*scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			req.Scopes = append(req.Scopes, volley.Scope(s))
+		}
+	}
+	if *expires > 0 {
+		at := time.Now().Add(*expires).UTC()
+		req.ExpiresAt = &at
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	token, err := client.Tokens.Create(req)
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+	t := tokenTable(token.APIToken)
+	t.headers = append(t.headers, "TOKEN")
+	t.rows[0] = append(t.rows[0], token.Token)
+	if err := a.render(token, t); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stderr, "Store the token now; it cannot be shown again.")
+	return nil
+}
+
+func tokensRevoke(a *app, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("token", pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Tokens.Revoke(id); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	fmt.Fprintf(a.stderr, "Revoked token %d\n", id)
+	return nil
+}
+
+func tokensCurrent(a *app, args []string) error {
+	fs := a.flags()
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Tokens.Current()
+	if err != nil {
+		return err
+	}
+	t := tokenTable(info.Token)
+	t.headers = append([]string{"USER", "EMAIL"}, t.headers...)
+	t.rows[0] = append([]string{formatID(info.UserID), info.Email}, t.rows[0]...)
+	return a.render(info, t)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+const (
+	watchPageSize = 100
+	// watchMaxPages bounds how far back a single poll catches up
+	watchMaxPages = 10
+	// watchWindow is how long events are remembered. An event whose status
+	// changes to match the filter later than this after it was received is
+	// not shown.
+	watchWindow = 15 * time.Minute
+)
+
+// eventWatcher polls Events.List and yields events it has not seen before in
+// the order they were received. Events are remembered by ID for watchWindow,
+// so an older event that comes to match a status filter after newer ones is
+// still shown.
+type eventWatcher struct {
+	client    *volley.Client
+	projectID uint64
+	filter    volley.ListEventsOptions
+	interval  time.Duration
+	// cursor is the highest event (request) ID seen. Events at or below the
+	// cursor the watcher starts with are never yielded.
+	cursor uint64
+	// backlog delivers events that already exist on the first poll; otherwise
+	// the first poll only records them. It is implied by a cursor.
+	backlog bool
+	// onError is called when a poll fails; polling continues at the next tick
+	onError func(error)
+
+	started bool
+	floor   uint64
+	seen    map[uint64]time.Time // creation time by event ID
+}
+
+// run polls until ctx is cancelled, calling fn for each new event
+func (w *eventWatcher) run(ctx context.Context, fn func(volley.Event) error) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.poll()
+		if err != nil && w.onError != nil {
+			w.onError(err)
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the events in the window and returns those not seen before
+func (w *eventWatcher) poll() ([]volley.Event, error) {
+	first := !w.started
+	if first {
+		w.started = true
+		w.floor = w.cursor
+		w.seen = make(map[uint64]time.Time)
+	}
+	skip := first && !w.backlog && w.cursor == 0
+
+	// The first poll of a backlog goes as far back as the filter does
+	cutoff := time.Now().Add(-watchWindow)
+	if first && w.backlog {
+		cutoff = time.Time{}
+	}
+
+	var fresh []volley.Event
+	limit := watchPageSize
+	for page := 0; page < watchMaxPages; page++ {
+		opts := w.filter
+		offset := page * watchPageSize
+		opts.Limit = &limit
+		opts.Offset = &offset
+
+		resp, err := w.client.Events.List(w.projectID, &opts)
+		if err != nil {
+			// Events already found are kept, so they are not yielded twice
+			return w.record(fresh, skip), err
+		}
+
+		// Events are listed newest first
+		reachedEnd := false
+		for _, e := range resp.Requests {
+			if e.ID <= w.floor || e.CreatedAt.Before(cutoff) {
+				reachedEnd = true
+				continue
+			}
+			if _, ok := w.seen[e.ID]; !ok {
+				fresh = append(fresh, e)
+			}
+		}
+		if reachedEnd || len(resp.Requests) < limit {
+			break
+		}
+	}
+
+	for id, created := range w.seen {
+		if created.Before(cutoff) {
+			delete(w.seen, id)
+		}
+	}
+	return w.record(fresh, skip), nil
+}
+
+// record marks events as seen and returns them in the order they were
+// received, or none if they only establish what has been seen
+func (w *eventWatcher) record(events []volley.Event, skip bool) []volley.Event {
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	for _, e := range events {
+		w.seen[e.ID] = e.CreatedAt
+		if e.ID > w.cursor {
+			w.cursor = e.ID
+		}
+	}
+	if skip {
+		return nil
+	}
+	return events
+}
+
+// sourceNames resolves source IDs to slugs for display, falling back to the ID
+type sourceNames map[uint64]string
+
+func loadSourceNames(client *volley.Client, projectID uint64) sourceNames {
+	names := make(sourceNames)
+	sources, err := client.Sources.List(projectID)
+	if err != nil {
+		return names
+	}
+	for _, s := range sources {
+		names[s.ID] = s.Slug
+	}
+	return names
+}
+
+func (n sourceNames) name(id uint64) string {
+	if slug, ok := n[id]; ok {
+		return slug
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+// resolve accepts a source ID or slug
+func (n sourceNames) resolve(s string) (uint64, bool) {
+	if id, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return id, true
+	}
+	for id, slug := range n {
+		if slug == s {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// eventsBackend serves a growing, newest-first list of events
+type eventsBackend struct {
+	mu     sync.Mutex
+	events []volley.Event
+}
+
+func (b *eventsBackend) add(id uint64, status, body string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := volley.Event{
+		ID:        id,
+		EventID:   fmt.Sprintf("evt_%d", id),
+		SourceID:  1,
+		ProjectID: 1,
+		RawBody:   body,
+		Headers:   map[string]interface{}{"X-Test": "yes"},
+		Status:    status,
+		CreatedAt: time.Now(),
+	}
+	b.events = append([]volley.Event{e}, b.events...)
+}
+
+func (b *eventsBackend) setStatus(id uint64, status string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.events {
+		if b.events[i].ID == id {
+			b.events[i].Status = status
+		}
+	}
+}
+
+func (b *eventsBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/projects/1/requests":
+		events := b.events
+		if status := r.URL.Query().Get("status"); status != "" {
+			events = nil
+			for _, e := range b.events {
+				if e.Status == status {
+					events = append(events, e)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(volley.ListEventsResponse{
+			PaginatedResponse: volley.PaginatedResponse{Total: int64(len(events)), Limit: 100},
+			Requests:          events,
+		})
+	case "/api/projects/1/sources":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sources": []volley.Source{{ID: 1, Slug: "stripe"}},
+		})
+	case "/api/projects/1/delivery-attempts":
+		json.NewEncoder(w).Encode(volley.ListDeliveryAttemptsResponse{
+			Attempts: []volley.DeliveryAttempt{{ID: 9, StatusCode: 502, DurationMs: 120, Status: "failed"}},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestEventWatcherPoll(t *testing.T) {
+	backend := &eventsBackend{}
+	backend.add(1, "processed", "{}")
+	backend.add(2, "failed", "{}")
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	w := &eventWatcher{client: client, projectID: 1}
+
+	// The first poll only establishes the cursor
+	events, err := w.poll()
+	if err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if len(events) != 0 || w.cursor != 2 {
+		t.Fatalf("Expected no events and cursor 2, got %d events and cursor %d", len(events), w.cursor)
+	}
+
+	backend.add(3, "failed", "{}")
+	backend.add(4, "processed", "{}")
+	events, err = w.poll()
+	if err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != 3 || events[1].ID != 4 {
+		t.Fatalf("Expected events 3 and 4 in order, got %+v", events)
+	}
+	if w.cursor != 4 {
+		t.Errorf("Expected cursor 4, got %d", w.cursor)
+	}
+
+	// Resuming from a cursor delivers everything after it
+	resumed := &eventWatcher{client: client, projectID: 1, cursor: 2}
+	events, _ = resumed.poll()
+	if len(events) != 2 {
+		t.Errorf("Expected 2 events after cursor 2, got %d", len(events))
+	}
+}
+
+func TestEventWatcherStatusChange(t *testing.T) {
+	backend := &eventsBackend{}
+	backend.add(1, "pending", "{}")
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	w := &eventWatcher{client: client, projectID: 1, filter: volley.ListEventsOptions{Status: "failed"}}
+	if _, err := w.poll(); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	// A newer event fails first, then the older one
+	backend.add(2, "failed", "{}")
+	if events, err := w.poll(); err != nil || len(events) != 1 || events[0].ID != 2 {
+		t.Fatalf("Expected event 2, got %+v (%v)", events, err)
+	}
+	backend.setStatus(1, "failed")
+	if events, err := w.poll(); err != nil || len(events) != 1 || events[0].ID != 1 {
+		t.Fatalf("Expected the older event once it failed, got %+v (%v)", events, err)
+	}
+	if events, err := w.poll(); err != nil || len(events) != 0 {
+		t.Errorf("Expected no events to be repeated, got %+v (%v)", events, err)
+	}
+}
+
+func TestEventWatcherKeepsPollingAfterErrors(t *testing.T) {
+	backend := &eventsBackend{}
+	var mu sync.Mutex
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fail := failures > 0
+		failures--
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		backend.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	backend.add(1, "processed", "{}")
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	var errs []error
+	w := &eventWatcher{client: client, projectID: 1, interval: time.Millisecond, backlog: true, onError: func(err error) { errs = append(errs, err) }}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got []uint64
+	err := w.run(ctx, func(e volley.Event) error {
+		got = append(got, e.ID)
+		cancel()
+		return nil
+	})
+	if err != nil || len(errs) != 1 || len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected one reported error and then event 1, got %v, errors %v and events %v", err, errs, got)
+	}
+}
+
+func TestTailPrinter(t *testing.T) {
+	backend := &eventsBackend{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	var out bytes.Buffer
+	p := &tailPrinter{out: &out, client: client, names: loadSourceNames(client, 1), body: true}
+
+	p.print(1, volley.Event{ID: 1, EventID: "evt_1", SourceID: 1, Status: "failed", RawBody: `{"a":1}`})
+
+	line := out.String()
+	for _, want := range []string{"evt_1", "stripe", "failed", "502", "120ms", `"a": 1`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, line)
+		}
+	}
+	if strings.Contains(line, "\033[") {
+		t.Error("Expected no color codes when color is disabled")
+	}
+}
@@ -0,0 +1,258 @@
+package volley
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+// Environment variables overlaid on the selected profile
+const (
+	EnvConfig     = "VOLLEY_CONFIG"      // config file path
+	EnvProfile    = "VOLLEY_PROFILE"     // profile name
+	EnvAPIToken   = "VOLLEY_API_TOKEN"   // API token, replacing any token source in the profile
+	EnvBaseURL    = "VOLLEY_BASE_URL"    // API base URL
+	EnvOrgID      = "VOLLEY_ORG_ID"      // organization ID
+	EnvProjectID  = "VOLLEY_PROJECT_ID"  // default project ID
+	EnvTimeout    = "VOLLEY_TIMEOUT"     // request timeout, e.g. "30s"
+	EnvMaxRetries = "VOLLEY_MAX_RETRIES" // retries of transient failures
+)
+
+// Config holds named connection profiles, typically loaded from
+// ~/.config/volley/config.yaml:
+//
+//	default_profile: staging
+//	profiles:
+//	  staging:
+//	    token_command: [vault, read, -field=token, secret/volley-staging]
+//	    org_id: 12
+//	    project_id: 34
+//	    retry:
+//	      max_retries: 3
+//	  prod:
+//	    token_file: ~/.volley/prod-token
+//	    timeout: 10s
+type Config struct {
+	DefaultProfile string              `json:"default_profile,omitempty" yaml:"default_profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles" yaml:"profiles"`
+}
+
+// Profile configures a client for one account, organization and project.
+// Exactly one of Token, TokenFile and TokenCommand should be set.
+type Profile struct {
+	// Name is the profile's key in the config
+	Name string `json:"-" yaml:"-"`
+
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+	// TokenFile is read on every request and re-read when it changes
+	TokenFile string `json:"token_file,omitempty" yaml:"token_file,omitempty"`
+	// TokenCommand is a command and its arguments printing the token
+	TokenCommand []string `json:"token_command,omitempty" yaml:"token_command,omitempty"`
+
+	BaseURL        string        `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	OrganizationID uint64        `json:"org_id,omitempty" yaml:"org_id,omitempty"`
+	ProjectID      uint64        `json:"project_id,omitempty" yaml:"project_id,omitempty"`
+	Timeout        time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry          *RetryPolicy  `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// DefaultConfigPath returns $VOLLEY_CONFIG, or config.yaml in
+// $XDG_CONFIG_HOME/volley (default ~/.config/volley)
+func DefaultConfigPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find config directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "volley", "config.yaml"), nil
+}
+
+// LoadConfig reads the config file at DefaultConfigPath. A missing file
+// yields an empty config, so profiles can come from the environment alone.
+func LoadConfig() (*Config, error) {
+	path, err := DefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadConfigFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return cfg, err
+}
+
+// LoadConfigFile reads and parses a config file
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Profile returns a copy of the named profile with the VOLLEY_* environment
+// variables overlaid. An empty name selects $VOLLEY_PROFILE, then the
+// config's default_profile, then "default". The default profile may be
+// absent from the file; any other name must exist.
+func (cfg *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile := &Profile{}
+	if p, ok := cfg.Profiles[name]; ok && p != nil {
+		*profile = *p
+		if p.Retry != nil {
+			retry := *p.Retry
+			profile.Retry = &retry
+		}
+	} else if name != DefaultProfile {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	profile.Name = name
+
+	if err := profile.applyEnv(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (p *Profile) applyEnv() error {
+	if token := os.Getenv(EnvAPIToken); token != "" {
+		p.Token, p.TokenFile, p.TokenCommand = token, "", nil
+	}
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		p.BaseURL = baseURL
+	}
+	if v := os.Getenv(EnvOrgID); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", EnvOrgID, v)
+		}
+		p.OrganizationID = id
+	}
+	if v := os.Getenv(EnvProjectID); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", EnvProjectID, v)
+		}
+		p.ProjectID = id
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", EnvTimeout, v)
+		}
+		p.Timeout = d
+	}
+	if v := os.Getenv(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s %q", EnvMaxRetries, v)
+		}
+		if p.Retry == nil {
+			p.Retry = &RetryPolicy{}
+		}
+		p.Retry.MaxRetries = n
+	}
+	return nil
+}
+
+// TokenProvider returns the provider for the profile's token source
+func (p *Profile) TokenProvider() (TokenProvider, error) {
+	switch {
+	case p.Token != "":
+		return StaticToken(p.Token), nil
+	case p.TokenFile != "":
+		return NewFileToken(expandHome(p.TokenFile)), nil
+	case len(p.TokenCommand) > 0:
+		return NewCommandToken(p.TokenCommand[0], p.TokenCommand[1:]...), nil
+	}
+	return nil, fmt.Errorf("no API token in profile %q: set token, token_file or token_command, or %s", p.Name, EnvAPIToken)
+}
+
+// ClientOptions returns the options configuring a client for the profile
+func (p *Profile) ClientOptions() ([]ClientOption, error) {
+	tokens, err := p.TokenProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []ClientOption{WithTokenProvider(tokens)}
+	if p.BaseURL != "" {
+		opts = append(opts, WithBaseURL(p.BaseURL))
+	}
+	if p.OrganizationID != 0 {
+		opts = append(opts, WithOrganizationID(p.OrganizationID))
+	}
+	if p.ProjectID != 0 {
+		opts = append(opts, WithProjectID(p.ProjectID))
+	}
+	if p.Timeout > 0 {
+		opts = append(opts, WithTimeout(p.Timeout))
+	}
+	if p.Retry != nil {
+		opts = append(opts, WithRetryPolicy(*p.Retry))
+	}
+	return opts, nil
+}
+
+// NewClient creates a client configured by the profile. Options are applied
+// after the profile's, so they take precedence.
+func (p *Profile) NewClient(opts ...ClientOption) (*Client, error) {
+	profileOpts, err := p.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient("", append(profileOpts, opts...)...), nil
+}
+
+// NewClientFromProfile loads the config and creates a client for the named
+// profile (see Config.Profile for how an empty name is resolved)
+func NewClientFromProfile(name string, opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cfg.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	return profile.NewClient(opts...)
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
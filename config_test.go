@@ -0,0 +1,149 @@
+package volley_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+const testConfig = `default_profile: staging
+profiles:
+  staging:
+    token: staging-token
+    org_id: 12
+    project_id: 34
+    timeout: 10s
+    retry:
+      max_retries: 3
+      backoff: 250ms
+  prod:
+    token_command: [echo, prod-token]
+`
+
+// writeConfig writes a config file and points VOLLEY_CONFIG at it
+func writeConfig(t *testing.T, data string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(volley.EnvConfig, path)
+	for _, name := range []string{volley.EnvProfile, volley.EnvAPIToken, volley.EnvBaseURL, volley.EnvOrgID, volley.EnvProjectID, volley.EnvTimeout, volley.EnvMaxRetries} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	writeConfig(t, testConfig)
+
+	cfg, err := volley.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	staging, err := cfg.Profile("")
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	if staging.Name != "staging" || staging.Token != "staging-token" || staging.OrganizationID != 12 || staging.ProjectID != 34 {
+		t.Errorf("Expected the default profile to be staging, got %+v", staging)
+	}
+	if staging.Timeout != 10*time.Second || staging.Retry == nil || staging.Retry.MaxRetries != 3 || staging.Retry.Backoff != 250*time.Millisecond {
+		t.Errorf("Unexpected timeout or retry policy: %v %+v", staging.Timeout, staging.Retry)
+	}
+
+	t.Setenv(volley.EnvProfile, "prod")
+	prod, err := cfg.Profile("")
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	provider, err := prod.TokenProvider()
+	if err != nil {
+		t.Fatalf("TokenProvider failed: %v", err)
+	}
+	if token, err := provider.Token(); err != nil || token != "prod-token" {
+		t.Errorf("Expected the token command's output, got %q (%v)", token, err)
+	}
+
+	if _, err := cfg.Profile("missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Errorf("Expected a missing profile error, got %v", err)
+	}
+}
+
+func TestConfigEnvironmentOverlay(t *testing.T) {
+	writeConfig(t, testConfig)
+	t.Setenv(volley.EnvAPIToken, "env-token")
+	t.Setenv(volley.EnvOrgID, "99")
+	t.Setenv(volley.EnvMaxRetries, "1")
+
+	cfg, err := volley.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	profile, err := cfg.Profile("prod")
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	if profile.Token != "env-token" || len(profile.TokenCommand) != 0 {
+		t.Errorf("Expected VOLLEY_API_TOKEN to replace the token command, got %+v", profile)
+	}
+	if profile.OrganizationID != 99 || profile.Retry == nil || profile.Retry.MaxRetries != 1 {
+		t.Errorf("Expected environment overrides, got %+v", profile)
+	}
+
+	// Overrides do not leak into the config
+	staging, _ := cfg.Profile("staging")
+	t.Setenv(volley.EnvMaxRetries, "")
+	again, _ := cfg.Profile("staging")
+	if staging.Retry.MaxRetries != 1 || again.Retry.MaxRetries != 3 {
+		t.Errorf("Expected profiles to be copies, got %d and %d", staging.Retry.MaxRetries, again.Retry.MaxRetries)
+	}
+
+	t.Setenv(volley.EnvOrgID, "acme")
+	if _, err := cfg.Profile("staging"); err == nil {
+		t.Error("Expected an invalid VOLLEY_ORG_ID to fail")
+	}
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	emu := emulator.New(&emulator.Options{Token: "file-token"})
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, "profiles:\n  local:\n    token_file: "+tokenFile+"\n    base_url: "+server.URL+"\n    org_id: 1\n    project_id: 1\n")
+
+	client, err := volley.NewClientFromProfile("local")
+	if err != nil {
+		t.Fatalf("NewClientFromProfile failed: %v", err)
+	}
+	if client.BaseURL() != server.URL || client.OrganizationID() == nil || *client.OrganizationID() != 1 {
+		t.Errorf("Expected the profile's base URL and organization, got %s %v", client.BaseURL(), client.OrganizationID())
+	}
+	if client.ProjectID() != 1 {
+		t.Errorf("Expected the profile's project, got %d", client.ProjectID())
+	}
+	if _, err := client.ListProjects(); err != nil {
+		t.Errorf("Expected the token file to authenticate, got %v", err)
+	}
+
+	// With no config file, the environment alone configures the default profile
+	t.Setenv(volley.EnvConfig, filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := volley.NewClientFromProfile(""); err == nil || !strings.Contains(err.Error(), volley.EnvAPIToken) {
+		t.Errorf("Expected a missing token error, got %v", err)
+	}
+	t.Setenv(volley.EnvAPIToken, "env-token")
+	if _, err := volley.NewClientFromProfile(""); err != nil {
+		t.Errorf("Expected VOLLEY_API_TOKEN alone to be enough, got %v", err)
+	}
+}
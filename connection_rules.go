@@ -0,0 +1,309 @@
+package volley
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleTarget is the part of an event a rule reads
+type RuleTarget string
+
+// Rule targets
+const (
+	TargetHeader RuleTarget = "header" // Path is a header name, matched case-insensitively
+	TargetBody   RuleTarget = "body"   // Path is a JSON body path such as "data.items.0.id"
+)
+
+// FilterOp is the comparison a FilterRule makes
+type FilterOp string
+
+// Filter operators. Body values are compared in their JSON text form, with
+// strings unquoted: 42, true, null or charge.succeeded.
+const (
+	OpEquals    FilterOp = "eq"
+	OpNotEquals FilterOp = "neq"
+	OpIn        FilterOp = "in" // equals one of Values
+	OpContains  FilterOp = "contains"
+	OpPrefix    FilterOp = "prefix"
+	OpMatches   FilterOp = "matches" // Value is a regular expression
+	OpGreater   FilterOp = "gt"      // numeric comparison
+	OpLess      FilterOp = "lt"      // numeric comparison
+	OpExists    FilterOp = "exists"
+	OpNotExists FilterOp = "not_exists"
+)
+
+// FilterRule is a condition an event must meet to be delivered through a
+// connection. A connection delivers an event only if all its filters match.
+type FilterRule struct {
+	Target RuleTarget `json:"target" yaml:"target"`
+	Path   string     `json:"path" yaml:"path"`
+	Op     FilterOp   `json:"op" yaml:"op"`
+	Value  string     `json:"value,omitempty" yaml:"value,omitempty"`
+	Values []string   `json:"values,omitempty" yaml:"values,omitempty"` // for OpIn
+}
+
+// Validate reports whether the rule is well formed
+func (f FilterRule) Validate() error {
+	if err := validateRulePath(f.Target, f.Path); err != nil {
+		return err
+	}
+	switch f.Op {
+	case OpEquals, OpNotEquals, OpContains, OpPrefix:
+	case OpIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("%s %q: values are required", f.Op, f.Path)
+		}
+		if f.Value != "" {
+			return fmt.Errorf("%s %q: use values instead of value", f.Op, f.Path)
+		}
+		return nil
+	case OpMatches:
+		if _, err := regexp.Compile(f.Value); err != nil {
+			return fmt.Errorf("%s %q: invalid regular expression: %w", f.Op, f.Path, err)
+		}
+	case OpGreater, OpLess:
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return fmt.Errorf("%s %q: value %q is not a number", f.Op, f.Path, f.Value)
+		}
+	case OpExists, OpNotExists:
+		if f.Value != "" {
+			return fmt.Errorf("%s %q: value is not allowed", f.Op, f.Path)
+		}
+	default:
+		return fmt.Errorf("invalid filter op %q", f.Op)
+	}
+	if len(f.Values) > 0 {
+		return fmt.Errorf("%s %q: values are only allowed with %s", f.Op, f.Path, OpIn)
+	}
+	return nil
+}
+
+// TransformType selects how a Transform builds the delivered body
+type TransformType string
+
+// Transform types
+const (
+	// TransformMap builds a JSON object from Mappings
+	TransformMap TransformType = "map"
+	// TransformTemplate renders Template, a text/template, with .Body (the
+	// decoded JSON body, or nil), .RawBody, .Headers (first value of each
+	// header) and .EventID. The json function encodes a value as JSON.
+	TransformTemplate TransformType = "template"
+)
+
+// FieldMapping copies a value from the original event into the transformed
+// body. Values that do not exist are left out.
+type FieldMapping struct {
+	Target RuleTarget `json:"target" yaml:"target"`
+	Path   string     `json:"path" yaml:"path"`
+	To     string     `json:"to" yaml:"to"` // body path in the transformed body
+}
+
+// Transform rewrites an event's body before it is delivered
+type Transform struct {
+	Type     TransformType  `json:"type" yaml:"type"`
+	Mappings []FieldMapping `json:"mappings,omitempty" yaml:"mappings,omitempty"`
+	Template string         `json:"template,omitempty" yaml:"template,omitempty"`
+	// ContentType replaces the Content-Type header of templated bodies
+	// (default: the original header)
+	ContentType string `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+}
+
+// Validate reports whether the transform is well formed
+func (t Transform) Validate() error {
+	switch t.Type {
+	case TransformMap:
+		if len(t.Mappings) == 0 {
+			return fmt.Errorf("map transform: mappings are required")
+		}
+		if t.Template != "" || t.ContentType != "" {
+			return fmt.Errorf("map transform: template and content_type are not allowed")
+		}
+		for i, m := range t.Mappings {
+			if err := validateRulePath(m.Target, m.Path); err != nil {
+				return fmt.Errorf("map transform: %w", err)
+			}
+			if err := validateRulePath(TargetBody, m.To); err != nil {
+				return fmt.Errorf("map transform: to: %w", err)
+			}
+			// Each target must be set by one mapping, without one nested in another
+			for _, prev := range t.Mappings[:i] {
+				switch {
+				case prev.To == m.To:
+					return fmt.Errorf("map transform: duplicate target %q", m.To)
+				case strings.HasPrefix(m.To, prev.To+"."), strings.HasPrefix(prev.To, m.To+"."):
+					return fmt.Errorf("map transform: targets %q and %q overlap", prev.To, m.To)
+				}
+			}
+		}
+	case TransformTemplate:
+		if len(t.Mappings) > 0 {
+			return fmt.Errorf("template transform: mappings are not allowed")
+		}
+		if _, err := t.ParseTemplate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid transform type %q", t.Type)
+	}
+	return nil
+}
+
+// ParseTemplate parses the template of a TransformTemplate transform
+func (t Transform) ParseTemplate() (*template.Template, error) {
+	if strings.TrimSpace(t.Template) == "" {
+		return nil, fmt.Errorf("template transform: template is required")
+	}
+	tmpl, err := template.New("transform").Option("missingkey=zero").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(t.Template)
+	if err != nil {
+		return nil, fmt.Errorf("template transform: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RetryType selects how the delay between delivery retries grows
+type RetryType string
+
+// Retry types
+const (
+	RetryFixed       RetryType = "fixed"       // wait IntervalSeconds before every retry
+	RetryExponential RetryType = "exponential" // double the delay after each retry, up to MaxBackoffSeconds
+)
+
+// RetryStrategy sets the delay between a connection's delivery retries. The
+// number of retries is the connection's MaxRetries.
+type RetryStrategy struct {
+	Type              RetryType `json:"type" yaml:"type"`
+	IntervalSeconds   int       `json:"interval_seconds" yaml:"interval_seconds"`
+	MaxBackoffSeconds int       `json:"max_backoff_seconds,omitempty" yaml:"max_backoff_seconds,omitempty"` // exponential only; 0 is uncapped
+}
+
+// Validate reports whether the strategy is well formed
+func (r RetryStrategy) Validate() error {
+	switch r.Type {
+	case RetryFixed:
+		if r.MaxBackoffSeconds != 0 {
+			return fmt.Errorf("fixed retry: max_backoff_seconds is not allowed")
+		}
+	case RetryExponential:
+		if r.MaxBackoffSeconds < 0 {
+			return fmt.Errorf("exponential retry: max_backoff_seconds must not be negative")
+		}
+		if r.MaxBackoffSeconds != 0 && r.MaxBackoffSeconds < r.IntervalSeconds {
+			return fmt.Errorf("exponential retry: max_backoff_seconds must be at least interval_seconds")
+		}
+	default:
+		return fmt.Errorf("invalid retry type %q", r.Type)
+	}
+	if r.IntervalSeconds <= 0 {
+		return fmt.Errorf("%s retry: interval_seconds must be positive", r.Type)
+	}
+	return nil
+}
+
+// Delay returns how long to wait before a retry, starting with retry 0
+func (r RetryStrategy) Delay(retry int) time.Duration {
+	delay := time.Duration(r.IntervalSeconds) * time.Second
+	if r.Type != RetryExponential {
+		return delay
+	}
+	max := time.Duration(r.MaxBackoffSeconds) * time.Second
+	for i := 0; i < retry; i++ {
+		if (max > 0 && delay >= max) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// ConnectionRules holds a connection's filters, transform and retry strategy,
+// as read from a rules file by LoadConnectionRules
+type ConnectionRules struct {
+	Filters   []FilterRule   `json:"filters,omitempty" yaml:"filters,omitempty"`
+	Transform *Transform     `json:"transform,omitempty" yaml:"transform,omitempty"`
+	Retry     *RetryStrategy `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// Validate checks the filters, transform and retry strategy
+func (r ConnectionRules) Validate() error {
+	return validateConnectionRules(r.Filters, r.Transform, r.Retry)
+}
+
+// LoadConnectionRules parses ConnectionRules from YAML or JSON
+func LoadConnectionRules(data []byte) (*ConnectionRules, error) {
+	var rules ConnectionRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadConnectionRulesFile reads and parses ConnectionRules from a YAML or JSON file
+func LoadConnectionRulesFile(path string) (*ConnectionRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return LoadConnectionRules(data)
+}
+
+// validateConnectionRules validates the rule fields of connection requests
+func validateConnectionRules(filters []FilterRule, transform *Transform, retry *RetryStrategy) error {
+	for i, f := range filters {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("invalid filter %d: %w", i, err)
+		}
+	}
+	if transform != nil {
+		if err := transform.Validate(); err != nil {
+			return fmt.Errorf("invalid transform: %w", err)
+		}
+	}
+	if retry != nil {
+		if err := retry.Validate(); err != nil {
+			return fmt.Errorf("invalid retry strategy: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRulePath checks a header name or a dot-separated body path
+func validateRulePath(target RuleTarget, path string) error {
+	switch target {
+	case TargetHeader:
+		if path == "" || strings.ContainsAny(path, " :\r\n") || http.CanonicalHeaderKey(path) == "" {
+			return fmt.Errorf("invalid header name %q", path)
+		}
+	case TargetBody:
+		for _, segment := range strings.Split(path, ".") {
+			if segment == "" {
+				return fmt.Errorf("invalid body path %q", path)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid rule target %q", target)
+	}
+	return nil
+}
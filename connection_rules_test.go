@@ -0,0 +1,193 @@
+package volley_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+const rulesJSON = `{"filters":[{"target":"header","path":"X-Event-Type","op":"in","values":["charge.succeeded","charge.refunded"]},{"target":"body","path":"data.amount","op":"gt","value":"100"},{"target":"body","path":"livemode","op":"exists"}],"transform":{"type":"map","mappings":[{"target":"body","path":"data.id","to":"charge.id"},{"target":"header","path":"X-Event-Type","to":"type"}]},"retry":{"type":"exponential","interval_seconds":5,"max_backoff_seconds":300}}`
+
+func TestConnectionRulesRoundTrip(t *testing.T) {
+	var rules volley.ConnectionRules
+	if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(encoded) != rulesJSON {
+		t.Errorf("Expected rules to round-trip exactly, got:\n%s", encoded)
+	}
+
+	fromYAML, err := volley.LoadConnectionRules([]byte(`filters:
+  - target: header
+    path: X-Event-Type
+    op: in
+    values: [charge.succeeded, charge.refunded]
+  - {target: body, path: data.amount, op: gt, value: "100"}
+  - {target: body, path: livemode, op: exists}
+transform:
+  type: map
+  mappings:
+    - {target: body, path: data.id, to: charge.id}
+    - {target: header, path: X-Event-Type, to: type}
+retry:
+  type: exponential
+  interval_seconds: 5
+  max_backoff_seconds: 300
+`))
+	if err != nil {
+		t.Fatalf("LoadConnectionRules failed: %v", err)
+	}
+	if !reflect.DeepEqual(*fromYAML, rules) {
+		t.Errorf("Expected YAML and JSON rules to match, got %+v", fromYAML)
+	}
+}
+
+func TestConnectionRulesValidate(t *testing.T) {
+	invalid := map[string]volley.ConnectionRules{
+		"unknown op":          {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: "like"}}},
+		"unknown target":      {Filters: []volley.FilterRule{{Target: "query", Path: "type", Op: volley.OpEquals}}},
+		"empty path segment":  {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "data..id", Op: volley.OpExists}}},
+		"in without values":   {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpIn, Value: "a"}}},
+		"values without in":   {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpEquals, Values: []string{"a"}}}},
+		"bad regexp":          {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpMatches, Value: "("}}},
+		"non-numeric gt":      {Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "amount", Op: volley.OpGreater, Value: "many"}}},
+		"empty map":           {Transform: &volley.Transform{Type: volley.TransformMap}},
+		"duplicate target":    {Transform: &volley.Transform{Type: volley.TransformMap, Mappings: []volley.FieldMapping{{Target: volley.TargetBody, Path: "a", To: "x"}, {Target: volley.TargetBody, Path: "b", To: "x"}}}},
+		"nested target":       {Transform: &volley.Transform{Type: volley.TransformMap, Mappings: []volley.FieldMapping{{Target: volley.TargetBody, Path: "a", To: "x.y"}, {Target: volley.TargetBody, Path: "b", To: "x"}}}},
+		"bad template":        {Transform: &volley.Transform{Type: volley.TransformTemplate, Template: "{{ .Body"}},
+		"unknown transform":   {Transform: &volley.Transform{Type: "jq"}},
+		"zero interval":       {Retry: &volley.RetryStrategy{Type: volley.RetryFixed}},
+		"cap below interval":  {Retry: &volley.RetryStrategy{Type: volley.RetryExponential, IntervalSeconds: 10, MaxBackoffSeconds: 5}},
+		"fixed with a cap":    {Retry: &volley.RetryStrategy{Type: volley.RetryFixed, IntervalSeconds: 10, MaxBackoffSeconds: 60}},
+		"unknown retry type":  {Retry: &volley.RetryStrategy{Type: "linear", IntervalSeconds: 10}},
+		"exists with a value": {Filters: []volley.FilterRule{{Target: volley.TargetHeader, Path: "X-Id", Op: volley.OpExists, Value: "1"}}},
+	}
+	for name, rules := range invalid {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	valid := volley.ConnectionRules{Transform: &volley.Transform{Type: volley.TransformTemplate, Template: `{"text": {{ json .Body.type }}}`, ContentType: "application/json"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a template transform to be valid, got %v", err)
+	}
+	siblings := volley.ConnectionRules{Transform: &volley.Transform{Type: volley.TransformMap, Mappings: []volley.FieldMapping{{Target: volley.TargetBody, Path: "a", To: "x.y"}, {Target: volley.TargetBody, Path: "b", To: "x.yz"}}}}
+	if err := siblings.Validate(); err != nil {
+		t.Errorf("Expected sibling targets to be valid, got %v", err)
+	}
+}
+
+func TestRetryStrategyDelay(t *testing.T) {
+	fixed := volley.RetryStrategy{Type: volley.RetryFixed, IntervalSeconds: 30}
+	exponential := volley.RetryStrategy{Type: volley.RetryExponential, IntervalSeconds: 5, MaxBackoffSeconds: 60}
+	tests := []struct {
+		strategy volley.RetryStrategy
+		retry    int
+		want     time.Duration
+	}{
+		{fixed, 0, 30 * time.Second},
+		{fixed, 5, 30 * time.Second},
+		{exponential, 0, 5 * time.Second},
+		{exponential, 2, 20 * time.Second},
+		{exponential, 4, 60 * time.Second},
+		{exponential, 100, 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.strategy.Delay(tt.retry); got != tt.want {
+			t.Errorf("%s retry %d: expected %v, got %v", tt.strategy.Type, tt.retry, tt.want, got)
+		}
+	}
+}
+
+func TestConnectionRules(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	src, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	dest, err := client.Destinations.Create(1, volley.CreateDestinationRequest{Name: "api", URL: "https://example.com/hooks"})
+	if err != nil {
+		t.Fatalf("Destinations.Create failed: %v", err)
+	}
+
+	var rules volley.ConnectionRules
+	if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := client.Connections.Create(1, volley.CreateConnectionRequest{
+		SourceID:      src.ID,
+		DestinationID: dest.ID,
+		MaxRetries:    3,
+		Filters:       rules.Filters,
+		Transform:     rules.Transform,
+		Retry:         rules.Retry,
+	})
+	if err != nil {
+		t.Fatalf("Connections.Create failed: %v", err)
+	}
+	got, err := client.Connections.Get(conn.ID)
+	if err != nil {
+		t.Fatalf("Connections.Get failed: %v", err)
+	}
+	if !reflect.DeepEqual(volley.ConnectionRules{Filters: got.Filters, Transform: got.Transform, Retry: got.Retry}, rules) {
+		t.Errorf("Expected the rules to be stored unchanged, got %+v", got)
+	}
+
+	// Exporting and planning again shows no changes
+	exported, err := client.ExportProject(1)
+	if err != nil {
+		t.Fatalf("ExportProject failed: %v", err)
+	}
+	if plan, err := client.PlanTopology(1, exported, nil); err != nil || !plan.Empty() {
+		t.Errorf("Expected an exported project to plan no changes, got %v (%v)", plan, err)
+	}
+
+	updated, err := client.Connections.Update(conn.ID, volley.UpdateConnectionRequest{RemoveFilters: true, RemoveTransform: true})
+	if err != nil {
+		t.Fatalf("Connections.Update failed: %v", err)
+	}
+	if len(updated.Filters) != 0 || updated.Transform != nil || !reflect.DeepEqual(updated.Retry, rules.Retry) {
+		t.Errorf("Expected only the filters and transform to be removed, got %+v", updated)
+	}
+
+	// Invalid rules are rejected before they are sent
+	_, err = client.Connections.Update(conn.ID, volley.UpdateConnectionRequest{Retry: &volley.RetryStrategy{Type: "linear"}})
+	var apiErr *volley.APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("Expected a local validation error, got %v", err)
+	}
+	filters := []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpExists}}
+	if _, err := client.Connections.Update(conn.ID, volley.UpdateConnectionRequest{Filters: filters, RemoveFilters: true}); err == nil || errors.As(err, &apiErr) {
+		t.Errorf("Expected filters with remove_filters to be rejected locally, got %v", err)
+	}
+}
+
+func TestUpdateConnectionOmitsUnsetFilters(t *testing.T) {
+	b, err := json.Marshal(volley.UpdateConnectionRequest{Status: "disabled"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"status":"disabled"}` {
+		t.Errorf("Expected only the status to be sent, got %s", b)
+	}
+}
@@ -4,17 +4,50 @@ import "fmt"
 
 // CreateConnectionRequest represents the request to create a connection
 type CreateConnectionRequest struct {
-	SourceID    uint64 `json:"source_id"`
-	DestinationID uint64 `json:"destination_id"`
-	Status      string `json:"status"` // "enabled" or "disabled"
-	EPS         int    `json:"eps"`
-	MaxRetries  int    `json:"max_retries"`
+	SourceID      uint64         `json:"source_id"`
+	DestinationID uint64         `json:"destination_id"`
+	Status        string         `json:"status"` // "enabled" or "disabled"
+	EPS           int            `json:"eps"`
+	MaxRetries    int            `json:"max_retries"`
+	Filters       []FilterRule   `json:"filters,omitempty"`
+	Transform     *Transform     `json:"transform,omitempty"`
+	Retry         *RetryStrategy `json:"retry,omitempty"`
 }
 
-// CreateConnection creates a connection between a source and destination
-func (c *Client) CreateConnection(projectID uint64, req CreateConnectionRequest) (*Connection, error) {
+// Validate checks the request's filters, transform and retry strategy
+func (r CreateConnectionRequest) Validate() error {
+	return validateConnectionRules(r.Filters, r.Transform, r.Retry)
+}
+
+// ConnectionsService manages connections between sources and destinations
+type ConnectionsService service
+
+// List lists all connections in a project
+func (s *ConnectionsService) List(projectID uint64) ([]Connection, error) {
+	path := fmt.Sprintf("/api/projects/%d/connections", projectID)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Connections []Connection `json:"connections"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Connections, nil
+}
+
+// Create creates a connection between a source and destination. Invalid
+// rules are rejected before the request is sent.
+func (s *ConnectionsService) Create(projectID uint64, req CreateConnectionRequest) (*Connection, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/api/projects/%d/connections", projectID)
-	resp, err := c.doRequest("POST", path, req, nil)
+	resp, err := s.client.doRequest("POST", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -22,17 +55,17 @@ func (c *Client) CreateConnection(projectID uint64, req CreateConnectionRequest)
 	var result struct {
 		Connection Connection `json:"connection"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Connection, nil
 }
 
-// GetConnection gets details and metrics for a connection
-func (c *Client) GetConnection(connectionID uint64) (*Connection, error) {
+// Get gets details and metrics for a connection
+func (s *ConnectionsService) Get(connectionID uint64) (*Connection, error) {
 	path := fmt.Sprintf("/api/connections/%d", connectionID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -40,24 +73,54 @@ func (c *Client) GetConnection(connectionID uint64) (*Connection, error) {
 	var result struct {
 		Connection Connection `json:"connection"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Connection, nil
 }
 
-// UpdateConnectionRequest represents the request to update a connection
+// UpdateConnectionRequest represents the request to update a connection.
+// Empty fields are left unchanged.
 type UpdateConnectionRequest struct {
 	Status     string `json:"status,omitempty"` // "enabled" or "disabled"
 	EPS        *int   `json:"eps,omitempty"`
 	MaxRetries *int   `json:"max_retries,omitempty"`
+
+	// Filters replaces the connection's filters when non-empty
+	Filters   []FilterRule   `json:"filters,omitempty"`
+	Transform *Transform     `json:"transform,omitempty"`
+	Retry     *RetryStrategy `json:"retry,omitempty"`
+	// RemoveFilters delivers every event again
+	RemoveFilters bool `json:"remove_filters,omitempty"`
+	// RemoveTransform delivers bodies unchanged again
+	RemoveTransform bool `json:"remove_transform,omitempty"`
+	// RemoveRetry restores the server's default backoff
+	RemoveRetry bool `json:"remove_retry,omitempty"`
 }
 
-// UpdateConnection updates a connection
-func (c *Client) UpdateConnection(connectionID uint64, req UpdateConnectionRequest) (*Connection, error) {
+// Validate checks the request's filters, transform and retry strategy
+func (r UpdateConnectionRequest) Validate() error {
+	if r.RemoveFilters && len(r.Filters) > 0 {
+		return fmt.Errorf("filters and remove_filters cannot be combined")
+	}
+	if r.RemoveTransform && r.Transform != nil {
+		return fmt.Errorf("transform and remove_transform cannot be combined")
+	}
+	if r.RemoveRetry && r.Retry != nil {
+		return fmt.Errorf("retry and remove_retry cannot be combined")
+	}
+	return validateConnectionRules(r.Filters, r.Transform, r.Retry)
+}
+
+// Update updates a connection. Invalid rules are rejected before the request
+// is sent.
+func (s *ConnectionsService) Update(connectionID uint64, req UpdateConnectionRequest) (*Connection, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/api/connections/%d", connectionID)
-	resp, err := c.doRequest("PUT", path, req, nil)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -65,21 +128,20 @@ func (c *Client) UpdateConnection(connectionID uint64, req UpdateConnectionReque
 	var result struct {
 		Connection Connection `json:"connection"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Connection, nil
 }
 
-// DeleteConnection deletes a connection
-func (c *Client) DeleteConnection(connectionID uint64) error {
+// Delete deletes a connection
+func (s *ConnectionsService) Delete(connectionID uint64) error {
 	path := fmt.Sprintf("/api/connections/%d", connectionID)
-	resp, err := c.doRequest("DELETE", path, nil, nil)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	return c.parseResponse(resp, nil)
+	return s.client.parseResponse(resp, nil)
 }
-
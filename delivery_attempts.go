@@ -20,8 +20,11 @@ type ListDeliveryAttemptsOptions struct {
 	Offset        *int
 }
 
-// ListDeliveryAttempts lists all delivery attempts for a project with optional filters
-func (c *Client) ListDeliveryAttempts(projectID uint64, opts *ListDeliveryAttemptsOptions) (*ListDeliveryAttemptsResponse, error) {
+// DeliveryAttemptsService lists delivery attempts
+type DeliveryAttemptsService service
+
+// List lists all delivery attempts for a project with optional filters
+func (s *DeliveryAttemptsService) List(projectID uint64, opts *ListDeliveryAttemptsOptions) (*ListDeliveryAttemptsResponse, error) {
 	path := fmt.Sprintf("/api/projects/%d/delivery-attempts", projectID)
 
 	params := make(map[string]string)
@@ -58,13 +61,13 @@ func (c *Client) ListDeliveryAttempts(projectID uint64, opts *ListDeliveryAttemp
 		}
 	}
 
-	resp, err := c.doRequest("GET", path, nil, params)
+	resp, err := s.client.doRequest("GET", path, nil, params)
 	if err != nil {
 		return nil, err
 	}
 
 	var result ListDeliveryAttemptsResponse
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,274 @@
+package volley
+
+import "time"
+
+// The flat Client methods below predate the resource services and remain
+// for compatibility. New code should use the services, e.g. client.Sources.List.
+
+// ListOrganizations lists all organizations the user has access to
+//
+// Deprecated: Use Client.Organizations.List.
+func (c *Client) ListOrganizations() ([]Organization, error) {
+	return c.Organizations.List()
+}
+
+// GetOrganization gets an organization. If organizationID is nil, the client's organization
+// is used, or the first accessible organization if none is set.
+//
+// Deprecated: Use Client.Organizations.Current or Client.Organizations.Get.
+func (c *Client) GetOrganization(organizationID *uint64) (*Organization, error) {
+	return c.Organizations.get(organizationID)
+}
+
+// CreateOrganization creates a new organization
+//
+// Deprecated: Use Client.Organizations.Create.
+func (c *Client) CreateOrganization(req CreateOrganizationRequest) (*Organization, error) {
+	return c.Organizations.Create(req)
+}
+
+// FindOrganizationBySlug returns the accessible organization with the given slug
+//
+// Deprecated: Use Client.Organizations.FindBySlug.
+func (c *Client) FindOrganizationBySlug(slug string) (*Organization, error) {
+	return c.Organizations.FindBySlug(slug)
+}
+
+// ListProjects lists all projects in the current organization
+//
+// Deprecated: Use Client.Projects.List.
+func (c *Client) ListProjects() ([]Project, error) {
+	return c.Projects.List()
+}
+
+// CreateProject creates a new project
+//
+// Deprecated: Use Client.Projects.Create.
+func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
+	return c.Projects.Create(req)
+}
+
+// UpdateProject updates a project's name
+//
+// Deprecated: Use Client.Projects.Update.
+func (c *Client) UpdateProject(projectID uint64, req UpdateProjectRequest) (*Project, error) {
+	return c.Projects.Update(projectID, req)
+}
+
+// DeleteProject deletes a project
+//
+// Deprecated: Use Client.Projects.Delete.
+func (c *Client) DeleteProject(projectID uint64) error {
+	return c.Projects.Delete(projectID)
+}
+
+// FindProjectByName returns the project with the given name in the current organization
+//
+// Deprecated: Use Client.Projects.FindByName.
+func (c *Client) FindProjectByName(name string) (*Project, error) {
+	return c.Projects.FindByName(name)
+}
+
+// DefaultProject returns the current organization's default project
+//
+// Deprecated: Use Client.Projects.Default.
+func (c *Client) DefaultProject() (*Project, error) {
+	return c.Projects.Default()
+}
+
+// ListSources lists all sources in a project
+//
+// Deprecated: Use Client.Sources.List.
+func (c *Client) ListSources(projectID uint64) ([]Source, error) {
+	return c.Sources.List(projectID)
+}
+
+// CreateSource creates a new source
+//
+// Deprecated: Use Client.Sources.Create.
+func (c *Client) CreateSource(projectID uint64, req CreateSourceRequest) (*Source, error) {
+	return c.Sources.Create(projectID, req)
+}
+
+// GetSource gets details of a specific source
+//
+// Deprecated: Use Client.Sources.Get.
+func (c *Client) GetSource(sourceID uint64) (*Source, error) {
+	return c.Sources.Get(sourceID)
+}
+
+// UpdateSource updates a source
+//
+// Deprecated: Use Client.Sources.Update.
+func (c *Client) UpdateSource(sourceID uint64, req UpdateSourceRequest) (*Source, error) {
+	return c.Sources.Update(sourceID, req)
+}
+
+// DeleteSource deletes a source
+//
+// Deprecated: Use Client.Sources.Delete.
+func (c *Client) DeleteSource(sourceID uint64) error {
+	return c.Sources.Delete(sourceID)
+}
+
+// FindSourceBySlug returns the source with the given slug in a project
+//
+// Deprecated: Use Client.Sources.FindBySlug.
+func (c *Client) FindSourceBySlug(projectID uint64, slug string) (*Source, error) {
+	return c.Sources.FindBySlug(projectID, slug)
+}
+
+// FindSourceByIngestionID returns the source with the given ingestion ID in a project
+//
+// Deprecated: Use Client.Sources.FindByIngestionID.
+func (c *Client) FindSourceByIngestionID(projectID uint64, ingestionID string) (*Source, error) {
+	return c.Sources.FindByIngestionID(projectID, ingestionID)
+}
+
+// RotateSourceSecret generates a new webhook secret for a source, accepting
+// the old one for gracePeriod
+//
+// Deprecated: Use Client.Sources.RotateSecret.
+func (c *Client) RotateSourceSecret(sourceID uint64, gracePeriod time.Duration) (*SourceSecret, error) {
+	return c.Sources.RotateSecret(sourceID, gracePeriod)
+}
+
+// SetSourceBasicAuth switches a source to basic auth, generating the password if empty
+//
+// Deprecated: Use Client.Sources.SetBasicAuth.
+func (c *Client) SetSourceBasicAuth(sourceID uint64, username, password string) (*SourceCredentials, error) {
+	return c.Sources.SetBasicAuth(sourceID, username, password)
+}
+
+// SetSourceAPIKey switches a source to API key auth, generating the key if empty
+//
+// Deprecated: Use Client.Sources.SetAPIKey.
+func (c *Client) SetSourceAPIKey(sourceID uint64, keyName, key string) (*SourceCredentials, error) {
+	return c.Sources.SetAPIKey(sourceID, keyName, key)
+}
+
+// ListDestinations lists all destinations in a project
+//
+// Deprecated: Use Client.Destinations.List.
+func (c *Client) ListDestinations(projectID uint64) ([]Destination, error) {
+	return c.Destinations.List(projectID)
+}
+
+// CreateDestination creates a new destination
+//
+// Deprecated: Use Client.Destinations.Create.
+func (c *Client) CreateDestination(projectID uint64, req CreateDestinationRequest) (*Destination, error) {
+	return c.Destinations.Create(projectID, req)
+}
+
+// GetDestination gets details of a specific destination
+//
+// Deprecated: Use Client.Destinations.Get.
+func (c *Client) GetDestination(destinationID uint64) (*Destination, error) {
+	return c.Destinations.Get(destinationID)
+}
+
+// UpdateDestination updates a destination
+//
+// Deprecated: Use Client.Destinations.Update.
+func (c *Client) UpdateDestination(destinationID uint64, req UpdateDestinationRequest) (*Destination, error) {
+	return c.Destinations.Update(destinationID, req)
+}
+
+// DeleteDestination deletes a destination
+//
+// Deprecated: Use Client.Destinations.Delete.
+func (c *Client) DeleteDestination(destinationID uint64) error {
+	return c.Destinations.Delete(destinationID)
+}
+
+// FindDestinationByName returns the destination with the given name in a project
+//
+// Deprecated: Use Client.Destinations.FindByName.
+func (c *Client) FindDestinationByName(projectID uint64, name string) (*Destination, error) {
+	return c.Destinations.FindByName(projectID, name)
+}
+
+// GetConnections lists all connections in a project
+//
+// Deprecated: Use Client.Connections.List.
+func (c *Client) GetConnections(projectID uint64) ([]Connection, error) {
+	return c.Connections.List(projectID)
+}
+
+// CreateConnection creates a connection between a source and destination
+//
+// Deprecated: Use Client.Connections.Create.
+func (c *Client) CreateConnection(projectID uint64, req CreateConnectionRequest) (*Connection, error) {
+	return c.Connections.Create(projectID, req)
+}
+
+// GetConnection gets details and metrics for a connection
+//
+// Deprecated: Use Client.Connections.Get.
+func (c *Client) GetConnection(connectionID uint64) (*Connection, error) {
+	return c.Connections.Get(connectionID)
+}
+
+// UpdateConnection updates a connection
+//
+// Deprecated: Use Client.Connections.Update.
+func (c *Client) UpdateConnection(connectionID uint64, req UpdateConnectionRequest) (*Connection, error) {
+	return c.Connections.Update(connectionID, req)
+}
+
+// DeleteConnection deletes a connection
+//
+// Deprecated: Use Client.Connections.Delete.
+func (c *Client) DeleteConnection(connectionID uint64) error {
+	return c.Connections.Delete(connectionID)
+}
+
+// FindConnection returns the connection from a source to a destination in a project
+//
+// Deprecated: Use Client.Connections.Find.
+func (c *Client) FindConnection(projectID, sourceID, destinationID uint64) (*Connection, error) {
+	return c.Connections.Find(projectID, sourceID, destinationID)
+}
+
+// ListEvents lists all events/requests for a project with optional filters
+//
+// Deprecated: Use Client.Events.List.
+func (c *Client) ListEvents(projectID uint64, opts *ListEventsOptions) (*ListEventsResponse, error) {
+	return c.Events.List(projectID, opts)
+}
+
+// GetEvent gets detailed information about a specific event by its database ID
+//
+// Deprecated: Use Client.Events.Get.
+func (c *Client) GetEvent(requestID uint64) (*Event, error) {
+	return c.Events.Get(requestID)
+}
+
+// ReplayEvent replays a failed event by its event_id
+//
+// Deprecated: Use Client.Events.Replay.
+func (c *Client) ReplayEvent(req ReplayEventRequest) (*ReplayEventResponse, error) {
+	return c.Events.Replay(req)
+}
+
+// ListDeliveryAttempts lists all delivery attempts for a project with optional filters
+//
+// Deprecated: Use Client.DeliveryAttempts.List.
+func (c *Client) ListDeliveryAttempts(projectID uint64, opts *ListDeliveryAttemptsOptions) (*ListDeliveryAttemptsResponse, error) {
+	return c.DeliveryAttempts.List(projectID, opts)
+}
+
+// IngestionURL returns the public URL webhooks are sent to for a source's ingestion ID
+//
+// Deprecated: Use Client.Ingestion.URL.
+func (c *Client) IngestionURL(ingestionID string) string {
+	return c.Ingestion.URL(ingestionID)
+}
+
+// SendWebhook sends a webhook to a source's ingestion ID
+//
+// Deprecated: Use Client.Ingestion.Send.
+func (c *Client) SendWebhook(sourceID string, payload interface{}) (string, error) {
+	return c.Ingestion.Send(sourceID, payload)
+}
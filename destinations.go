@@ -1,11 +1,17 @@
 package volley
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+)
 
-// ListDestinations lists all destinations in a project
-func (c *Client) ListDestinations(projectID uint64) ([]Destination, error) {
+// DestinationsService manages webhook destinations
+type DestinationsService service
+
+// List lists all destinations in a project
+func (s *DestinationsService) List(projectID uint64) ([]Destination, error) {
 	path := fmt.Sprintf("/api/projects/%d/destinations", projectID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -13,7 +19,7 @@ func (c *Client) ListDestinations(projectID uint64) ([]Destination, error) {
 	var result struct {
 		Destinations []Destination `json:"destinations"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -22,15 +28,38 @@ func (c *Client) ListDestinations(projectID uint64) ([]Destination, error) {
 
 // CreateDestinationRequest represents the request to create a destination
 type CreateDestinationRequest struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
-	EPS  int    `json:"eps"`
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	EPS            int               `json:"eps"`
+	Method         string            `json:"method,omitempty"` // default "POST"
+	Headers        map[string]string `json:"headers,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+
+	// AuthType selects the credentials sent with deliveries: "bearer" sends
+	// AuthToken, "basic" sends AuthUsername and AuthPassword
+	AuthType     string `json:"auth_type,omitempty"`
+	AuthUsername string `json:"auth_username,omitempty"`
+	AuthPassword Secret `json:"auth_password,omitempty"`
+	AuthToken    Secret `json:"auth_token,omitempty"`
+
+	// ClientCert and ClientKey are a PEM encoded certificate and private key
+	// presented to destinations that require mutual TLS
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  Secret `json:"client_key,omitempty"`
+}
+
+// LogValue redacts the request's secrets in log/slog output, including
+// handlers such as slog.JSONHandler that encode the request as JSON
+func (r CreateDestinationRequest) LogValue() slog.Value {
+	type plain CreateDestinationRequest
+	r.AuthPassword, r.AuthToken, r.ClientKey = r.AuthPassword.redacted(), r.AuthToken.redacted(), r.ClientKey.redacted()
+	return slog.AnyValue(plain(r))
 }
 
-// CreateDestination creates a new destination
-func (c *Client) CreateDestination(projectID uint64, req CreateDestinationRequest) (*Destination, error) {
+// Create creates a new destination
+func (s *DestinationsService) Create(projectID uint64, req CreateDestinationRequest) (*Destination, error) {
 	path := fmt.Sprintf("/api/projects/%d/destinations", projectID)
-	resp, err := c.doRequest("POST", path, req, nil)
+	resp, err := s.client.doRequest("POST", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,17 +67,17 @@ func (c *Client) CreateDestination(projectID uint64, req CreateDestinationReques
 	var result struct {
 		Destination Destination `json:"destination"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Destination, nil
 }
 
-// GetDestination gets details of a specific destination
-func (c *Client) GetDestination(destinationID uint64) (*Destination, error) {
+// Get gets details of a specific destination
+func (s *DestinationsService) Get(destinationID uint64) (*Destination, error) {
 	path := fmt.Sprintf("/api/destinations/%d", destinationID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -56,25 +85,63 @@ func (c *Client) GetDestination(destinationID uint64) (*Destination, error) {
 	var result struct {
 		Destination Destination `json:"destination"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Destination, nil
 }
 
-// UpdateDestinationRequest represents the request to update a destination
+// UpdateDestinationRequest represents the request to update a destination.
+// Empty fields are left unchanged.
 type UpdateDestinationRequest struct {
-	Name   string `json:"name,omitempty"`
-	URL    string `json:"url,omitempty"`
-	EPS    *int   `json:"eps,omitempty"`
-	Status string `json:"status,omitempty"`
+	Name           string `json:"name,omitempty"`
+	URL            string `json:"url,omitempty"`
+	EPS            *int   `json:"eps,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Method         string `json:"method,omitempty"`
+	TimeoutSeconds *int   `json:"timeout_seconds,omitempty"`
+
+	// Headers replaces the destination's static headers when non-empty
+	Headers map[string]string `json:"headers,omitempty"`
+	// RemoveHeaders removes all static headers
+	RemoveHeaders bool `json:"remove_headers,omitempty"`
+
+	// AuthType "none" removes the destination's credentials
+	AuthType     string `json:"auth_type,omitempty"`
+	AuthUsername string `json:"auth_username,omitempty"`
+	AuthPassword Secret `json:"auth_password,omitempty"`
+	AuthToken    Secret `json:"auth_token,omitempty"`
+
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  Secret `json:"client_key,omitempty"`
+	// RemoveClientCert stops presenting a client certificate
+	RemoveClientCert bool `json:"remove_client_cert,omitempty"`
+}
+
+// LogValue redacts the request's secrets in log/slog output
+func (r UpdateDestinationRequest) LogValue() slog.Value {
+	type plain UpdateDestinationRequest
+	r.AuthPassword, r.AuthToken, r.ClientKey = r.AuthPassword.redacted(), r.AuthToken.redacted(), r.ClientKey.redacted()
+	return slog.AnyValue(plain(r))
 }
 
-// UpdateDestination updates a destination
-func (c *Client) UpdateDestination(destinationID uint64, req UpdateDestinationRequest) (*Destination, error) {
+// Validate checks that the request does not both set and remove the headers
+func (r UpdateDestinationRequest) Validate() error {
+	if r.RemoveHeaders && len(r.Headers) > 0 {
+		return fmt.Errorf("headers and remove_headers cannot be combined")
+	}
+	return nil
+}
+
+// Update updates a destination. Invalid requests are rejected before they
+// are sent.
+func (s *DestinationsService) Update(destinationID uint64, req UpdateDestinationRequest) (*Destination, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/api/destinations/%d", destinationID)
-	resp, err := c.doRequest("PUT", path, req, nil)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,21 +149,21 @@ func (c *Client) UpdateDestination(destinationID uint64, req UpdateDestinationRe
 	var result struct {
 		Destination Destination `json:"destination"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Destination, nil
 }
 
-// DeleteDestination deletes a destination
-func (c *Client) DeleteDestination(destinationID uint64) error {
+// Delete deletes a destination
+func (s *DestinationsService) Delete(destinationID uint64) error {
 	path := fmt.Sprintf("/api/destinations/%d", destinationID)
-	resp, err := c.doRequest("DELETE", path, nil, nil)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	return c.parseResponse(resp, nil)
+	return s.client.parseResponse(resp, nil)
 }
 
@@ -0,0 +1,288 @@
+package volley
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DriftType classifies a difference between a spec and live state
+type DriftType string
+
+const (
+	// DriftMissing means an object in the spec does not exist live
+	DriftMissing DriftType = "missing"
+	// DriftChanged means a field differs between spec and live state
+	DriftChanged DriftType = "changed"
+	// DriftDisabled means a connection the spec expects enabled is disabled
+	DriftDisabled DriftType = "disabled"
+	// DriftRenamed means a missing object appears to exist live under another name
+	DriftRenamed DriftType = "renamed"
+	// DriftOrphaned means a live object is not described by the spec
+	DriftOrphaned DriftType = "orphaned"
+	// DriftDangling means a live connection references a deleted source or destination
+	DriftDangling DriftType = "dangling"
+)
+
+// DriftItem is a single difference found by Drift
+type DriftItem struct {
+	Type     DriftType    `json:"type"`
+	Kind     ResourceKind `json:"kind"`
+	Name     string       `json:"name"`
+	ID       uint64       `json:"id,omitempty"`
+	Field    string       `json:"field,omitempty"`
+	Expected interface{}  `json:"expected,omitempty"`
+	Actual   interface{}  `json:"actual,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// DriftReport lists every difference between a spec and a project's live state
+type DriftReport struct {
+	ProjectID uint64      `json:"project_id"`
+	CheckedAt time.Time   `json:"checked_at"`
+	Items     []DriftItem `json:"items"`
+}
+
+// HasDrift reports whether any differences were found
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Items) > 0
+}
+
+func (r *DriftReport) String() string {
+	if !r.HasDrift() {
+		return "No drift detected.\n"
+	}
+	var b strings.Builder
+	for _, item := range r.Items {
+		fmt.Fprintf(&b, "[%s] %s %s: %s\n", item.Type, item.Kind, item.Name, item.Message)
+	}
+	return b.String()
+}
+
+// Drift compares a spec, such as a stored topology or a previous ExportProject
+// snapshot, against a project's live state. It is read-only.
+func (c *Client) Drift(projectID uint64, spec *TopologySpec) (*DriftReport, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	live, err := c.fetchLiveTopology(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{ProjectID: projectID, CheckedAt: time.Now().UTC()}
+	add := func(item DriftItem) {
+		report.Items = append(report.Items, item)
+	}
+
+	// Index what the spec expects so unmatched live objects can be found
+	wantSources := make(map[string]SourceSpec)
+	for _, s := range spec.Sources {
+		wantSources[s.Slug] = s
+	}
+	wantDestinations := make(map[string]DestinationSpec)
+	for _, d := range spec.Destinations {
+		wantDestinations[d.Name] = d
+	}
+	wantConnections := make(map[string]ConnectionSpec)
+	for _, conn := range spec.Connections {
+		wantConnections[conn.Key()] = conn
+	}
+
+	renamedSources := detectSourceRenames(spec, live, wantSources)
+	renamedDestinations := detectDestinationRenames(spec, live, wantDestinations)
+
+	for _, want := range spec.Sources {
+		have, ok := live.sources[want.Slug]
+		if !ok {
+			if liveSlug, renamed := renamedSources[want.Slug]; renamed {
+				add(DriftItem{Type: DriftRenamed, Kind: KindSource, Name: want.Slug, ID: live.sources[liveSlug].ID,
+					Expected: want.Slug, Actual: liveSlug, Message: fmt.Sprintf("source appears to have been renamed to %q", liveSlug)})
+			} else {
+				add(DriftItem{Type: DriftMissing, Kind: KindSource, Name: want.Slug, Message: "source does not exist"})
+			}
+			continue
+		}
+		for _, f := range diffSource(have, &want) {
+			add(changedItem(KindSource, want.Slug, have.ID, f))
+		}
+	}
+
+	for _, want := range spec.Destinations {
+		have, ok := live.destinations[want.Name]
+		if !ok {
+			if liveName, renamed := renamedDestinations[want.Name]; renamed {
+				add(DriftItem{Type: DriftRenamed, Kind: KindDestination, Name: want.Name, ID: live.destinations[liveName].ID,
+					Expected: want.Name, Actual: liveName, Message: fmt.Sprintf("destination appears to have been renamed to %q", liveName)})
+			} else {
+				add(DriftItem{Type: DriftMissing, Kind: KindDestination, Name: want.Name, Message: "destination does not exist"})
+			}
+			continue
+		}
+		for _, f := range diffDestination(have, &want) {
+			add(changedItem(KindDestination, want.Name, have.ID, f))
+		}
+	}
+
+	for _, want := range spec.Connections {
+		have, ok := live.connections[want.Key()]
+		if !ok {
+			add(DriftItem{Type: DriftMissing, Kind: KindConnection, Name: want.Key(), Message: "connection does not exist"})
+			continue
+		}
+		disabled := have.Status == "disabled" && want.Status != "disabled"
+		if disabled {
+			add(DriftItem{Type: DriftDisabled, Kind: KindConnection, Name: want.Key(), ID: have.ID, Field: "status",
+				Expected: defaultString(want.Status, "enabled"), Actual: have.Status, Message: "connection is disabled"})
+		}
+		for _, f := range diffConnection(have, &want) {
+			// A disabled connection is reported as such rather than as a changed status
+			if disabled && f.Field == "status" {
+				continue
+			}
+			add(changedItem(KindConnection, want.Key(), have.ID, f))
+		}
+	}
+
+	renamedLive := make(map[string]bool)
+	for _, slug := range renamedSources {
+		renamedLive["source:"+slug] = true
+	}
+	for _, name := range renamedDestinations {
+		renamedLive["destination:"+name] = true
+	}
+
+	for slug, s := range live.sources {
+		if _, ok := wantSources[slug]; !ok && !renamedLive["source:"+slug] {
+			add(DriftItem{Type: DriftOrphaned, Kind: KindSource, Name: slug, ID: s.ID, Message: "source is not in the spec"})
+		}
+	}
+	for name, d := range live.destinations {
+		if _, ok := wantDestinations[name]; !ok && !renamedLive["destination:"+name] {
+			add(DriftItem{Type: DriftOrphaned, Kind: KindDestination, Name: name, ID: d.ID, Message: "destination is not in the spec"})
+		}
+	}
+	for key, conn := range live.connections {
+		if _, ok := wantConnections[key]; !ok {
+			add(DriftItem{Type: DriftOrphaned, Kind: KindConnection, Name: key, ID: conn.ID, Message: "connection is not in the spec"})
+		}
+	}
+	for _, conn := range live.orphans {
+		slug, okSource := live.sourceSlugs[conn.SourceID]
+		name, okDest := live.destinationNames[conn.DestinationID]
+		switch {
+		case !okDest:
+			add(DriftItem{Type: DriftDangling, Kind: KindConnection, Name: fmt.Sprintf("#%d", conn.ID), ID: conn.ID,
+				Field: "destination_id", Actual: conn.DestinationID, Message: fmt.Sprintf("connection points at deleted destination %d", conn.DestinationID)})
+		case !okSource:
+			add(DriftItem{Type: DriftDangling, Kind: KindConnection, Name: fmt.Sprintf("#%d", conn.ID), ID: conn.ID,
+				Field: "source_id", Actual: conn.SourceID, Message: fmt.Sprintf("connection points at deleted source %d", conn.SourceID)})
+		default:
+			add(DriftItem{Type: DriftOrphaned, Kind: KindConnection, Name: connectionKey(slug, name), ID: conn.ID,
+				Message: "duplicate connection"})
+		}
+	}
+
+	rank := map[ResourceKind]int{KindSource: 0, KindDestination: 1, KindConnection: 2}
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Kind != b.Kind {
+			return rank[a.Kind] < rank[b.Kind]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Field < b.Field
+	})
+
+	return report, nil
+}
+
+func changedItem(kind ResourceKind, name string, id uint64, f FieldChange) DriftItem {
+	return DriftItem{
+		Type:     DriftChanged,
+		Kind:     kind,
+		Name:     name,
+		ID:       id,
+		Field:    f.Field,
+		Expected: f.To,
+		Actual:   f.From,
+		Message:  fmt.Sprintf("%s is %v, expected %v", f.Field, f.From, f.To),
+	}
+}
+
+// detectDestinationRenames pairs missing spec destinations with unmanaged live
+// destinations that have the same URL. It returns spec name -> live name.
+func detectDestinationRenames(spec *TopologySpec, live *liveTopology, want map[string]DestinationSpec) map[string]string {
+	candidates := make([]string, 0, len(live.destinations))
+	for name := range live.destinations {
+		candidates = append(candidates, name)
+	}
+	sort.Strings(candidates)
+
+	renamed := make(map[string]string)
+	claimed := make(map[string]bool)
+	for _, d := range spec.Destinations {
+		if _, ok := live.destinations[d.Name]; ok {
+			continue
+		}
+		for _, name := range candidates {
+			if _, managed := want[name]; managed || claimed[name] {
+				continue
+			}
+			if live.destinations[name].URL == d.URL {
+				renamed[d.Name] = name
+				claimed[name] = true
+				break
+			}
+		}
+	}
+	return renamed
+}
+
+// detectSourceRenames pairs missing spec sources with unmanaged live sources
+// that connect to the same set of destinations. It returns spec slug -> live slug.
+func detectSourceRenames(spec *TopologySpec, live *liveTopology, want map[string]SourceSpec) map[string]string {
+	wantTargets := make(map[string][]string)
+	for _, conn := range spec.Connections {
+		wantTargets[conn.Source] = append(wantTargets[conn.Source], conn.Destination)
+	}
+	liveTargets := make(map[string][]string)
+	for key := range live.connections {
+		parts := strings.SplitN(key, " -> ", 2)
+		liveTargets[parts[0]] = append(liveTargets[parts[0]], parts[1])
+	}
+	signature := func(targets []string) string {
+		sorted := append([]string(nil), targets...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, "\x00")
+	}
+
+	candidates := make([]string, 0, len(live.sources))
+	for slug := range live.sources {
+		candidates = append(candidates, slug)
+	}
+	sort.Strings(candidates)
+
+	renamed := make(map[string]string)
+	claimed := make(map[string]bool)
+	for _, s := range spec.Sources {
+		if _, ok := live.sources[s.Slug]; ok || len(wantTargets[s.Slug]) == 0 {
+			continue
+		}
+		sig := signature(wantTargets[s.Slug])
+		for _, slug := range candidates {
+			if _, managed := want[slug]; managed || claimed[slug] {
+				continue
+			}
+			if signature(liveTargets[slug]) == sig {
+				renamed[s.Slug] = slug
+				claimed[slug] = true
+				break
+			}
+		}
+	}
+	return renamed
+}
@@ -0,0 +1,91 @@
+package volley_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func TestDrift(t *testing.T) {
+	backend := newProjectBackend()
+	// "stripe" was renamed to "stripe-live" in the console
+	stripe := backend.addSource("stripe-live", 10)
+	github := backend.addSource("github", 20)
+	backend.addSource("scratch", 1)
+	api := backend.addDestination("api", "https://new.example.com/hooks", 5)
+	backend.addConnection(stripe.ID, api.ID, 5, 3)
+	disabled := backend.addConnection(github.ID, api.ID, 5, 3)
+	disabled.Status = "disabled"
+	backend.addConnection(github.ID, 9999, 5, 3)
+
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	spec, err := volley.LoadTopology([]byte(`
+sources:
+  - slug: stripe
+    eps: 10
+  - slug: github
+    eps: 5
+destinations:
+  - name: api
+    url: https://api.example.com/hooks
+    eps: 5
+connections:
+  - source: stripe
+    destination: api
+    eps: 5
+    max_retries: 3
+  - source: github
+    destination: api
+    eps: 5
+    max_retries: 3
+`))
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+
+	report, err := client.Drift(1, spec)
+	if err != nil {
+		t.Fatalf("Drift failed: %v", err)
+	}
+
+	var got []string
+	for _, item := range report.Items {
+		got = append(got, fmt.Sprintf("%s %s %s %s", item.Type, item.Kind, item.Name, item.Field))
+	}
+	want := []string{
+		"changed source github eps",
+		"orphaned source scratch ",
+		"renamed source stripe ",
+		"changed destination api url",
+		"dangling connection #" + fmt.Sprint(backend.nextID) + " destination_id",
+		"disabled connection github -> api status",
+		"missing connection stripe -> api ",
+		"orphaned connection stripe-live -> api ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected drift:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Reports are machine readable
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"renamed"`) {
+		t.Errorf("Expected renamed item in JSON output, got %s", data)
+	}
+
+	// Drift is read-only
+	for _, call := range backend.calls {
+		if !strings.HasPrefix(call, "GET ") {
+			t.Errorf("Drift made a mutating request: %s", call)
+		}
+	}
+}
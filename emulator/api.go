@@ -0,0 +1,1058 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/rules"
+)
+
+// currentOrg resolves the X-Organization-ID header, defaulting to the first organization
+func (s *Server) currentOrg(w http.ResponseWriter, r *http.Request) (*volley.Organization, bool) {
+	header := r.Header.Get("X-Organization-ID")
+	if header == "" {
+		ids := sortedIDs(s.orgs)
+		if len(ids) == 0 {
+			replyError(w, http.StatusNotFound, "organization not found")
+			return nil, false
+		}
+		return s.orgs[ids[0]], true
+	}
+
+	id, err := strconv.ParseUint(header, 10, 64)
+	org, ok := s.orgs[id]
+	if err != nil || !ok {
+		replyError(w, http.StatusNotFound, "organization not found")
+		return nil, false
+	}
+	return org, true
+}
+
+func (s *Server) project(w http.ResponseWriter, id uint64) (*volley.Project, bool) {
+	p, ok := s.projects[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "project not found")
+	}
+	return p, ok
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request, _ uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orgs := []volley.Organization{}
+	for _, id := range sortedIDs(s.orgs) {
+		if s.callerMember(id) != nil {
+			orgs = append(orgs, s.orgView(s.orgs[id]))
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"organizations": orgs})
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request, _ uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, ok := s.currentOrg(w, r)
+	if !ok {
+		return
+	}
+	reply(w, http.StatusOK, s.orgView(org))
+}
+
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request, _ uint64) {
+	var req volley.CreateOrganizationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		replyError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org := &volley.Organization{
+		ID:        s.nextID("org"),
+		Name:      req.Name,
+		Slug:      slugify(req.Name),
+		AccountID: 1,
+		Role:      string(volley.RoleOwner),
+		CreatedAt: time.Now().UTC(),
+	}
+	s.orgs[org.ID] = org
+	s.addMember(org.ID, callerUserID, callerEmail, volley.RoleOwner)
+	reply(w, http.StatusCreated, org)
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req volley.UpdateOrganizationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, ok := s.organization(w, id)
+	if !ok || !s.requireRole(w, id, volley.RoleAdmin) {
+		return
+	}
+	if req.Name != "" {
+		org.Name = req.Name
+		org.Slug = slugify(req.Name)
+	}
+	reply(w, http.StatusOK, s.orgView(org))
+}
+
+func (s *Server) deleteOrganization(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.organization(w, id); !ok || !s.requireRole(w, id, volley.RoleOwner) {
+		return
+	}
+	for _, pid := range sortedIDs(s.projects) {
+		if s.projects[pid].OrganizationID == id {
+			s.removeProject(pid)
+		}
+	}
+	for _, mid := range sortedIDs(s.members) {
+		if s.members[mid].OrganizationID == id {
+			delete(s.members, mid)
+		}
+	}
+	for _, iid := range sortedIDs(s.invites) {
+		if s.invites[iid].OrganizationID == id {
+			delete(s.invites, iid)
+		}
+	}
+	delete(s.orgs, id)
+	reply(w, http.StatusOK, map[string]string{"message": "organization deleted"})
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, _ uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, ok := s.currentOrg(w, r)
+	if !ok {
+		return
+	}
+	projects := []volley.Project{}
+	for _, id := range sortedIDs(s.projects) {
+		if p := s.projects[id]; p.OrganizationID == org.ID {
+			projects = append(projects, *p)
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"projects": projects})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, _ uint64) {
+	var req volley.CreateProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		replyError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, ok := s.currentOrg(w, r)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	p := &volley.Project{
+		ID:             s.nextID("project"),
+		Name:           req.Name,
+		OrganizationID: org.ID,
+		IsDefault:      req.IsDefault,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.projects[p.ID] = p
+	reply(w, http.StatusCreated, map[string]interface{}{"project": p})
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req volley.UpdateProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.project(w, id)
+	if !ok {
+		return
+	}
+	if req.Name != "" {
+		p.Name = req.Name
+	}
+	p.UpdatedAt = time.Now().UTC()
+	reply(w, http.StatusOK, map[string]interface{}{"project": p})
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, id); !ok {
+		return
+	}
+	s.removeProject(id)
+	reply(w, http.StatusOK, map[string]string{"message": "project deleted"})
+}
+
+// removeProject deletes a project with its sources, destinations and connections
+func (s *Server) removeProject(id uint64) {
+	for _, cid := range sortedIDs(s.connections) {
+		if s.connections[cid].projectID == id {
+			s.removeConnection(cid)
+		}
+	}
+	for _, sid := range sortedIDs(s.sources) {
+		if s.sources[sid].projectID == id {
+			delete(s.sources, sid)
+		}
+	}
+	for _, did := range sortedIDs(s.destinations) {
+		if s.destinations[did].projectID == id {
+			delete(s.destinations, did)
+		}
+	}
+	delete(s.projects, id)
+}
+
+// sourceView returns the API representation of a source
+func (s *Server) sourceView(src *source) volley.Source {
+	view := src.Source
+	view.WebhookSecretSet = src.webhookSecret != ""
+	view.ConnectionCount = 0
+	for _, c := range s.connections {
+		if c.SourceID == src.ID {
+			view.ConnectionCount++
+		}
+	}
+	return view
+}
+
+func (s *Server) listSources(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	sources := []volley.Source{}
+	for _, id := range sortedIDs(s.sources) {
+		if src := s.sources[id]; src.projectID == projectID {
+			sources = append(sources, s.sourceView(src))
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"sources": sources})
+}
+
+func (s *Server) createSource(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	var req volley.CreateSourceRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	slug := slugify(req.Name)
+	if slug == "" {
+		replyError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if req.EPS < 0 {
+		replyError(w, http.StatusBadRequest, "eps must not be negative")
+		return
+	}
+	authType := req.AuthType
+	if authType == "" {
+		authType = "none"
+	}
+	if !validAuthType(authType) {
+		replyError(w, http.StatusBadRequest, fmt.Sprintf("invalid auth_type %q", req.AuthType))
+		return
+	}
+	if req.VerifySignature && req.WebhookSecret == "" {
+		replyError(w, http.StatusBadRequest, "webhook_secret is required to verify signatures")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	for _, src := range s.sources {
+		if src.projectID == projectID && src.Slug == slug {
+			replyError(w, http.StatusConflict, fmt.Sprintf("source %q already exists", slug))
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	src := &source{
+		Source: volley.Source{
+			ID:          s.nextID("source"),
+			Slug:        slug,
+			IngestionID: randomHex(12),
+			Type:        "webhook",
+			EPS:         req.EPS,
+			Status:      "active",
+			AuthType:    authType,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		projectID: projectID,
+	}
+	src.VerifySignature = req.VerifySignature
+	src.webhookSecret = string(req.WebhookSecret)
+	src.setCredentials(req.AuthUsername, string(req.AuthPassword), req.AuthKeyName, string(req.AuthKey))
+	s.sources[src.ID] = src
+	reply(w, http.StatusCreated, map[string]interface{}{"source": s.sourceView(src)})
+}
+
+func (s *Server) getSource(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, ok := s.sources[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"source": s.sourceView(src)})
+}
+
+func (s *Server) updateSource(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req volley.UpdateSourceRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, ok := s.sources[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	if req.EPS != nil && *req.EPS < 0 {
+		replyError(w, http.StatusBadRequest, "eps must not be negative")
+		return
+	}
+	if req.AuthType != "" && !validAuthType(req.AuthType) {
+		replyError(w, http.StatusBadRequest, fmt.Sprintf("invalid auth_type %q", req.AuthType))
+		return
+	}
+	if req.VerifySignature != nil && *req.VerifySignature && req.WebhookSecret == "" && src.webhookSecret == "" {
+		replyError(w, http.StatusBadRequest, "webhook_secret is required to verify signatures")
+		return
+	}
+	if req.Name != "" {
+		src.Slug = slugify(req.Name)
+	}
+	if req.EPS != nil {
+		src.EPS = *req.EPS
+	}
+	if req.AuthType != "" {
+		src.AuthType = req.AuthType
+	}
+	src.setCredentials(defaultString(req.AuthUsername, src.AuthUsername), defaultString(string(req.AuthPassword), src.authPassword),
+		defaultString(req.AuthKeyName, src.AuthKeyName), defaultString(string(req.AuthKey), src.authKey))
+	if req.WebhookSecret != "" {
+		src.webhookSecret, src.previousSecret = string(req.WebhookSecret), ""
+	}
+	if req.VerifySignature != nil {
+		src.VerifySignature = *req.VerifySignature
+	}
+	if req.Status != "" {
+		src.Status = req.Status
+	}
+	src.UpdatedAt = time.Now().UTC()
+	reply(w, http.StatusOK, map[string]interface{}{"source": s.sourceView(src)})
+}
+
+func (s *Server) deleteSource(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sources[id]; !ok {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	for _, cid := range sortedIDs(s.connections) {
+		if s.connections[cid].SourceID == id {
+			s.removeConnection(cid)
+		}
+	}
+	delete(s.sources, id)
+	reply(w, http.StatusOK, map[string]string{"message": "source deleted"})
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (s *Server) listDestinations(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	destinations := []volley.Destination{}
+	for _, id := range sortedIDs(s.destinations) {
+		if d := s.destinations[id]; d.projectID == projectID {
+			destinations = append(destinations, d.view())
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"destinations": destinations})
+}
+
+func (s *Server) createDestination(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	var req volley.CreateDestinationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		replyError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if !validURL(req.URL) {
+		replyError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+	if req.EPS < 0 {
+		replyError(w, http.StatusBadRequest, "eps must not be negative")
+		return
+	}
+	if msg := checkDestinationOptions(req.Method, &req.TimeoutSeconds, req.AuthType, req.Headers); msg != "" {
+		replyError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	now := time.Now().UTC()
+	d := &destination{
+		Destination: volley.Destination{
+			Name:           req.Name,
+			URL:            req.URL,
+			EPS:            req.EPS,
+			Status:         "active",
+			Method:         defaultString(req.Method, http.MethodPost),
+			Headers:        req.Headers,
+			TimeoutSeconds: req.TimeoutSeconds,
+			AuthType:       defaultString(req.AuthType, "none"),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+		projectID: projectID,
+	}
+	if msg := d.setAuth(req.AuthUsername, string(req.AuthPassword), string(req.AuthToken)); msg != "" {
+		replyError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if msg := s.setClientCert(d, req.ClientCert, string(req.ClientKey)); msg != "" {
+		replyError(w, http.StatusBadRequest, msg)
+		return
+	}
+	d.ID = s.nextID("destination")
+	s.destinations[d.ID] = d
+	reply(w, http.StatusCreated, map[string]interface{}{"destination": d.view()})
+}
+
+func (s *Server) getDestination(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.destinations[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "destination not found")
+		return
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"destination": d.view()})
+}
+
+func (s *Server) updateDestination(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req volley.UpdateDestinationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.URL != "" && !validURL(req.URL) {
+		replyError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+	if req.EPS != nil && *req.EPS < 0 {
+		replyError(w, http.StatusBadRequest, "eps must not be negative")
+		return
+	}
+	if msg := checkDestinationOptions(req.Method, req.TimeoutSeconds, req.AuthType, req.Headers); msg != "" {
+		replyError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if req.RemoveClientCert && (req.ClientCert != "" || req.ClientKey != "") {
+		replyError(w, http.StatusBadRequest, "remove_client_cert cannot be combined with a new client certificate")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.destinations[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "destination not found")
+		return
+	}
+	if req.AuthType != "" || req.AuthUsername != "" || req.AuthPassword != "" || req.AuthToken != "" {
+		updated := *d
+		updated.AuthType = defaultString(req.AuthType, d.AuthType)
+		if msg := updated.setAuth(defaultString(req.AuthUsername, d.AuthUsername),
+			defaultString(string(req.AuthPassword), d.authPassword), defaultString(string(req.AuthToken), d.authToken)); msg != "" {
+			replyError(w, http.StatusBadRequest, msg)
+			return
+		}
+		d.AuthType, d.AuthUsername, d.authPassword, d.authToken = updated.AuthType, updated.AuthUsername, updated.authPassword, updated.authToken
+	}
+	if req.ClientCert != "" || req.ClientKey != "" || req.RemoveClientCert {
+		if msg := s.setClientCert(d, req.ClientCert, string(req.ClientKey)); msg != "" {
+			replyError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+	if req.Name != "" {
+		d.Name = req.Name
+	}
+	if req.URL != "" {
+		d.URL = req.URL
+	}
+	if req.EPS != nil {
+		d.EPS = *req.EPS
+	}
+	if req.Status != "" {
+		d.Status = req.Status
+	}
+	if req.Method != "" {
+		d.Method = req.Method
+	}
+	if req.TimeoutSeconds != nil {
+		d.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.RemoveHeaders {
+		d.Headers = nil
+	}
+	if len(req.Headers) > 0 {
+		d.Headers = req.Headers
+	}
+	d.UpdatedAt = time.Now().UTC()
+	reply(w, http.StatusOK, map[string]interface{}{"destination": d.view()})
+}
+
+func (s *Server) deleteDestination(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.destinations[id]; !ok {
+		replyError(w, http.StatusNotFound, "destination not found")
+		return
+	}
+	for _, cid := range sortedIDs(s.connections) {
+		if s.connections[cid].DestinationID == id {
+			s.removeConnection(cid)
+		}
+	}
+	delete(s.destinations, id)
+	reply(w, http.StatusOK, map[string]string{"message": "destination deleted"})
+}
+
+func validConnectionStatus(status string) bool {
+	return status == "enabled" || status == "disabled"
+}
+
+// compileRule prepares a connection's filters and transform for delivery, or
+// returns nil if it has neither
+func compileRule(filters []volley.FilterRule, transform *volley.Transform) (*rules.Rule, error) {
+	if len(filters) == 0 && transform == nil {
+		return nil, nil
+	}
+	return rules.Compile(volley.ConnectionRules{Filters: filters, Transform: transform})
+}
+
+func (s *Server) listConnections(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	connections := []volley.Connection{}
+	for _, id := range sortedIDs(s.connections) {
+		if c := s.connections[id]; c.projectID == projectID {
+			connections = append(connections, c.Connection)
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"connections": connections})
+}
+
+func (s *Server) createConnection(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	var req volley.CreateConnectionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Status == "" {
+		req.Status = "enabled"
+	}
+	if !validConnectionStatus(req.Status) {
+		replyError(w, http.StatusBadRequest, `status must be "enabled" or "disabled"`)
+		return
+	}
+	if req.EPS < 0 || req.MaxRetries < 0 {
+		replyError(w, http.StatusBadRequest, "eps and max_retries must not be negative")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+	if src, ok := s.sources[req.SourceID]; !ok || src.projectID != projectID {
+		replyError(w, http.StatusBadRequest, "source not found in project")
+		return
+	}
+	if d, ok := s.destinations[req.DestinationID]; !ok || d.projectID != projectID {
+		replyError(w, http.StatusBadRequest, "destination not found in project")
+		return
+	}
+	for _, c := range s.connections {
+		if c.SourceID == req.SourceID && c.DestinationID == req.DestinationID {
+			replyError(w, http.StatusConflict, "connection already exists")
+			return
+		}
+	}
+
+	rule, err := compileRule(req.Filters, req.Transform)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	c := &connection{
+		Connection: volley.Connection{
+			ID:            s.nextID("connection"),
+			SourceID:      req.SourceID,
+			DestinationID: req.DestinationID,
+			Status:        req.Status,
+			EPS:           req.EPS,
+			MaxRetries:    req.MaxRetries,
+			Filters:       req.Filters,
+			Transform:     req.Transform,
+			Retry:         req.Retry,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		},
+		projectID: projectID,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		rule:      rule,
+	}
+	s.connections[c.ID] = c
+	s.wg.Add(1)
+	go s.work(c)
+	reply(w, http.StatusCreated, map[string]interface{}{"connection": c.Connection})
+}
+
+func (s *Server) getConnection(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.connections[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "connection not found")
+		return
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"connection": c.Connection})
+}
+
+func (s *Server) updateConnection(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req volley.UpdateConnectionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Status != "" && !validConnectionStatus(req.Status) {
+		replyError(w, http.StatusBadRequest, `status must be "enabled" or "disabled"`)
+		return
+	}
+	if (req.EPS != nil && *req.EPS < 0) || (req.MaxRetries != nil && *req.MaxRetries < 0) {
+		replyError(w, http.StatusBadRequest, "eps and max_retries must not be negative")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.connections[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "connection not found")
+		return
+	}
+	filters, transform := c.Filters, c.Transform
+	if req.RemoveFilters {
+		filters = nil
+	}
+	if len(req.Filters) > 0 {
+		filters = req.Filters
+	}
+	if req.Transform != nil || req.RemoveTransform {
+		transform = req.Transform
+	}
+	rule, err := compileRule(filters, transform)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Status != "" {
+		c.Status = req.Status
+	}
+	if req.EPS != nil {
+		c.EPS = *req.EPS
+	}
+	if req.MaxRetries != nil {
+		c.MaxRetries = *req.MaxRetries
+	}
+	if req.Retry != nil || req.RemoveRetry {
+		c.Retry = req.Retry
+	}
+	c.Filters, c.Transform, c.rule = filters, transform, rule
+	c.UpdatedAt = time.Now().UTC()
+	reply(w, http.StatusOK, map[string]interface{}{"connection": c.Connection})
+}
+
+func (s *Server) deleteConnection(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.connections[id]; !ok {
+		replyError(w, http.StatusNotFound, "connection not found")
+		return
+	}
+	s.removeConnection(id)
+	reply(w, http.StatusOK, map[string]string{"message": "connection deleted"})
+}
+
+// listQuery holds the query parameters shared by the event and attempt listings
+type listQuery struct {
+	values        url.Values
+	sourceID      *uint64
+	connectionID  *uint64
+	destinationID *uint64
+	start, end    *time.Time
+	limit, offset int
+}
+
+func parseListQuery(r *http.Request) (*listQuery, error) {
+	q := &listQuery{values: r.URL.Query(), limit: DefaultPageSize}
+
+	for name, dst := range map[string]**uint64{
+		"source_id":      &q.sourceID,
+		"connection_id":  &q.connectionID,
+		"destination_id": &q.destinationID,
+	} {
+		if v := q.values.Get(name); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			*dst = &id
+		}
+	}
+	for name, dst := range map[string]**time.Time{"start_time": &q.start, "end_time": &q.end} {
+		if v := q.values.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			*dst = &t
+		}
+	}
+	for name, dst := range map[string]*int{"limit": &q.limit, "offset": &q.offset} {
+		if v := q.values.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			*dst = n
+		}
+	}
+	return q, nil
+}
+
+// inRange reports whether t falls within the query's time window
+func (q *listQuery) inRange(t time.Time) bool {
+	return (q.start == nil || !t.Before(*q.start)) && (q.end == nil || !t.After(*q.end))
+}
+
+// page returns the [offset, offset+limit) window of n items
+func (q *listQuery) page(n int) (int, int) {
+	from := q.offset
+	if from > n {
+		from = n
+	}
+	to := from + q.limit
+	if to > n {
+		to = n
+	}
+	return from, to
+}
+
+// eventView returns the API representation of an event with its attempts
+func (s *Server) eventView(ev *event) volley.Event {
+	view := ev.Event
+	view.DeliveryAttempts = nil
+	for _, at := range s.attempts {
+		if at.EventID == ev.EventID {
+			view.DeliveryAttempts = append(view.DeliveryAttempts, at.DeliveryAttempt)
+		}
+	}
+	return view
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	q, err := parseListQuery(r)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+
+	status := q.values.Get("status")
+	search := q.values.Get("search")
+	var matched []*event
+	// Events are stored oldest first and listed newest first
+	for i := len(s.events) - 1; i >= 0; i-- {
+		ev := s.events[i]
+		if ev.ProjectID != projectID || !q.inRange(ev.CreatedAt) {
+			continue
+		}
+		if q.sourceID != nil && ev.SourceID != *q.sourceID {
+			continue
+		}
+		if status != "" && ev.Status != status {
+			continue
+		}
+		if search != "" && !strings.Contains(ev.RawBody, search) && !strings.Contains(ev.EventID, search) {
+			continue
+		}
+		if (q.connectionID != nil || q.destinationID != nil) && !s.eventRoutedTo(ev, q.connectionID, q.destinationID) {
+			continue
+		}
+		matched = append(matched, ev)
+	}
+
+	from, to := q.page(len(matched))
+	events := []volley.Event{}
+	for _, ev := range matched[from:to] {
+		events = append(events, s.eventView(ev))
+	}
+	reply(w, http.StatusOK, volley.ListEventsResponse{
+		PaginatedResponse: volley.PaginatedResponse{Total: int64(len(matched)), Limit: q.limit, Offset: q.offset},
+		Requests:          events,
+	})
+}
+
+// eventRoutedTo reports whether an event was attempted through the connection
+// or to the destination
+func (s *Server) eventRoutedTo(ev *event, connectionID, destinationID *uint64) bool {
+	for _, at := range s.attempts {
+		if at.EventID != ev.EventID {
+			continue
+		}
+		if (connectionID == nil || at.ConnectionID == *connectionID) &&
+			(destinationID == nil || at.destinationID == *destinationID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getEvent(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ev := range s.events {
+		if ev.ID == id {
+			reply(w, http.StatusOK, map[string]interface{}{"request": s.eventView(ev)})
+			return
+		}
+	}
+	replyError(w, http.StatusNotFound, "request not found")
+}
+
+func (s *Server) listDeliveryAttempts(w http.ResponseWriter, r *http.Request, projectID uint64) {
+	q, err := parseListQuery(r)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	order := q.values.Get("sort")
+	if order != "" && order != "time" && order != "time_oldest" && order != "duration" && order != "status_code" {
+		replyError(w, http.StatusBadRequest, "invalid sort")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.project(w, projectID); !ok {
+		return
+	}
+
+	eventID := q.values.Get("event_id")
+	status := q.values.Get("status")
+	var matched []volley.DeliveryAttempt
+	for _, at := range s.attempts {
+		if at.projectID != projectID || !q.inRange(at.CreatedAt) {
+			continue
+		}
+		if (eventID != "" && at.EventID != eventID) || (status != "" && at.Status != status) {
+			continue
+		}
+		if (q.sourceID != nil && at.sourceID != *q.sourceID) ||
+			(q.connectionID != nil && at.ConnectionID != *q.connectionID) ||
+			(q.destinationID != nil && at.destinationID != *q.destinationID) {
+			continue
+		}
+		matched = append(matched, at.DeliveryAttempt)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		switch order {
+		case "time_oldest":
+			return a.ID < b.ID
+		case "duration":
+			if a.DurationMs != b.DurationMs {
+				return a.DurationMs > b.DurationMs
+			}
+		case "status_code":
+			if a.StatusCode != b.StatusCode {
+				return a.StatusCode > b.StatusCode
+			}
+		}
+		return a.ID > b.ID
+	})
+
+	from, to := q.page(len(matched))
+	attempts := append([]volley.DeliveryAttempt{}, matched[from:to]...)
+	reply(w, http.StatusOK, volley.ListDeliveryAttemptsResponse{
+		PaginatedResponse: volley.PaginatedResponse{Total: int64(len(matched)), Limit: q.limit, Offset: q.offset},
+		Attempts:          attempts,
+	})
+}
+
+func (s *Server) replayEvent(w http.ResponseWriter, r *http.Request, _ uint64) {
+	var req volley.ReplayEventRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	var ev *event
+	for _, e := range s.events {
+		if e.EventID == req.EventID {
+			ev = e
+		}
+	}
+	if ev == nil {
+		s.mu.Unlock()
+		replyError(w, http.StatusNotFound, "event not found")
+		return
+	}
+
+	var targets []*connection
+	for _, id := range sortedIDs(s.connections) {
+		c := s.connections[id]
+		switch {
+		case req.ConnectionID != nil:
+			if c.ID == *req.ConnectionID && c.projectID == ev.ProjectID {
+				targets = append(targets, c)
+			}
+		case req.DestinationID != nil:
+			if c.SourceID == ev.SourceID && c.DestinationID == *req.DestinationID {
+				targets = append(targets, c)
+			}
+		default:
+			if c.SourceID == ev.SourceID && c.Status != "disabled" {
+				targets = append(targets, c)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if len(targets) == 0 {
+		replyError(w, http.StatusBadRequest, "no connection to replay the event through")
+		return
+	}
+
+	// Report the first failure, or the last success when every replay succeeded
+	var result *attempt
+	for _, c := range targets {
+		at := s.attempt(c, ev)
+		if result == nil || result.Status == "success" {
+			result = at
+		}
+	}
+
+	s.mu.Lock()
+	if result.Status == "success" && ev.pending == 0 {
+		ev.Status = "processed"
+	}
+	s.mu.Unlock()
+
+	reply(w, http.StatusOK, volley.ReplayEventResponse{
+		Success:     result.Status == "success",
+		Status:      result.Status,
+		StatusCode:  result.StatusCode,
+		ErrorReason: result.ErrorReason,
+		DurationMs:  result.DurationMs,
+		AttemptID:   result.ID,
+	})
+}
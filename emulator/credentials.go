@@ -0,0 +1,160 @@
+package emulator
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func validAuthType(authType string) bool {
+	return authType == "none" || authType == "basic" || authType == "api_key"
+}
+
+// setCredentials sets the credentials used by the source's auth type and clears the rest
+func (src *source) setCredentials(username, password, keyName, key string) {
+	src.AuthUsername, src.authPassword, src.AuthKeyName, src.authKey = "", "", "", ""
+	switch src.AuthType {
+	case "basic":
+		src.AuthUsername, src.authPassword = username, password
+	case "api_key":
+		src.AuthKeyName, src.authKey = keyName, key
+	}
+}
+
+// credentialHeaders returns the canonical names of the headers that carry the
+// source's credentials, which are not forwarded to destinations
+func (src *source) credentialHeaders() map[string]bool {
+	switch src.AuthType {
+	case "basic":
+		return map[string]bool{"Authorization": true}
+	case "api_key":
+		return map[string]bool{http.CanonicalHeaderKey(src.AuthKeyName): true}
+	}
+	return nil
+}
+
+// authenticate checks a webhook against the source's credentials and
+// signature secret. Basic and API key auth are enforced once their secret is set.
+func (src *source) authenticate(r *http.Request, body []byte, now time.Time) bool {
+	switch {
+	case src.AuthType == "basic" && src.authPassword != "":
+		username, password, ok := r.BasicAuth()
+		if !ok || !equal(username, src.AuthUsername) || !equal(password, src.authPassword) {
+			return false
+		}
+	case src.AuthType == "api_key" && src.authKey != "":
+		if !equal(r.Header.Get(src.AuthKeyName), src.authKey) {
+			return false
+		}
+	}
+
+	if !src.VerifySignature {
+		return true
+	}
+	signature := r.Header.Get(volley.SignatureHeader)
+	if equal(signature, volley.SignWebhook(src.webhookSecret, body)) {
+		return true
+	}
+	return src.previousSecret != "" && now.Before(src.previousExpires) &&
+		equal(signature, volley.SignWebhook(src.previousSecret, body))
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func (s *Server) rotateSourceSecret(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req struct {
+		GracePeriodSeconds int64 `json:"grace_period_seconds"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.GracePeriodSeconds < 0 {
+		replyError(w, http.StatusBadRequest, "grace_period_seconds must not be negative")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, ok := s.sources[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+
+	now := time.Now().UTC()
+	secret := "whsec_" + randomHex(24)
+	result := volley.SourceSecret{WebhookSecret: volley.Secret(secret)}
+	src.previousSecret = ""
+	if req.GracePeriodSeconds > 0 && src.webhookSecret != "" {
+		expires := now.Add(time.Duration(req.GracePeriodSeconds) * time.Second)
+		src.previousSecret, src.previousExpires = src.webhookSecret, expires
+		result.PreviousSecretExpiresAt = &expires
+	}
+	src.webhookSecret = secret
+	src.UpdatedAt = now
+	result.Source = s.sourceView(src)
+	reply(w, http.StatusOK, result)
+}
+
+func (s *Server) setSourceAuth(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req struct {
+		AuthType string `json:"auth_type"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		KeyName  string `json:"key_name"`
+		Key      string `json:"key"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	var result volley.SourceCredentials
+	switch req.AuthType {
+	case "basic":
+		if req.Username == "" {
+			replyError(w, http.StatusBadRequest, "username is required")
+			return
+		}
+		if req.Password == "" {
+			req.Password = randomHex(16)
+			result.Password = volley.Secret(req.Password)
+		}
+	case "api_key":
+		if req.KeyName == "" {
+			replyError(w, http.StatusBadRequest, "key_name is required")
+			return
+		}
+		if req.Key == "" {
+			req.Key = randomHex(24)
+			result.Key = volley.Secret(req.Key)
+		}
+	default:
+		replyError(w, http.StatusBadRequest, "auth_type must be basic or api_key")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, ok := s.sources[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	src.AuthType = req.AuthType
+	src.setCredentials(req.Username, req.Password, req.KeyName, req.Key)
+	src.UpdatedAt = time.Now().UTC()
+	result.Source = s.sourceView(src)
+	reply(w, http.StatusOK, result)
+}
@@ -0,0 +1,448 @@
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// maxBodySize is the largest webhook body the emulator accepts
+const maxBodySize = 10 << 20
+
+// hopHeaders are not copied from the original webhook when delivering it
+var hopHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Accept-Encoding":   true,
+	"Transfer-Encoding": true,
+	"Keep-Alive":        true,
+	"Upgrade":           true,
+}
+
+// delivery is a queued attempt to deliver an event through a connection
+type delivery struct {
+	event *event
+	// retry is 0 for the first attempt
+	retry int
+}
+
+// limiter is a token bucket allowing eps events per second with bursts of eps
+type limiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// take consumes a token and returns zero, or returns how long to wait until
+// one is available. An eps of zero or less is unlimited.
+func (l *limiter) take(eps int, now time.Time) time.Duration {
+	if eps <= 0 {
+		return 0
+	}
+
+	rate := float64(eps)
+	if l.last.IsZero() {
+		l.tokens = rate
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * rate
+		if l.tokens > rate {
+			l.tokens = rate
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// WaitIdle blocks until no deliveries are queued, running or waiting for a
+// retry, or until ctx is done
+func (s *Server) WaitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		idle := s.inflight == 0
+		s.mu.Unlock()
+		if idle {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ingest stores a webhook sent to a source and queues its deliveries
+func (s *Server) ingest(w http.ResponseWriter, r *http.Request, ingestionID string) {
+	if r.Method != http.MethodPost {
+		replyError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		replyError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	if len(body) > maxBodySize {
+		replyError(w, http.StatusRequestEntityTooLarge, "body too large")
+		return
+	}
+
+	headers := make(map[string]interface{}, len(r.Header))
+	for name, values := range r.Header {
+		if len(values) == 1 {
+			headers[name] = values[0]
+			continue
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			list[i] = v
+		}
+		headers[name] = list
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var src *source
+	for _, candidate := range s.sources {
+		if candidate.IngestionID == ingestionID {
+			src = candidate
+		}
+	}
+	if src == nil {
+		replyError(w, http.StatusNotFound, "source not found")
+		return
+	}
+
+	now := time.Now()
+	if !src.authenticate(r, body, now) {
+		replyError(w, http.StatusUnauthorized, "invalid credentials or signature")
+		return
+	}
+	if src.limiter.take(src.EPS, now) > 0 {
+		replyError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	ev := &event{Event: volley.Event{
+		ID:        s.nextID("event"),
+		EventID:   "evt_" + randomHex(12),
+		SourceID:  src.ID,
+		ProjectID: src.projectID,
+		RawBody:   string(body),
+		Headers:   headers,
+		Status:    "pending",
+		CreatedAt: now.UTC(),
+	}}
+	s.events = append(s.events, ev)
+
+	if src.Status != "disabled" {
+		for _, id := range sortedIDs(s.connections) {
+			c := s.connections[id]
+			if c.SourceID != src.ID || c.Status == "disabled" {
+				continue
+			}
+			if d := s.destinations[c.DestinationID]; d == nil || d.Status == "disabled" {
+				continue
+			}
+			if c.rule != nil {
+				// Events that cannot be evaluated are not delivered, like those that do not match
+				if matched, _ := c.rule.Match(ev.Event); !matched {
+					continue
+				}
+			}
+			if s.enqueue(c, delivery{event: ev}) {
+				ev.pending++
+				s.inflight++
+			}
+		}
+	}
+	if ev.pending == 0 {
+		ev.Status = "dropped"
+	}
+
+	reply(w, http.StatusAccepted, map[string]string{"event_id": ev.EventID})
+}
+
+// enqueue queues a delivery on a connection that still exists. The caller must hold s.mu.
+func (s *Server) enqueue(c *connection, d delivery) bool {
+	if s.connections[c.ID] != c || s.isClosed() {
+		return false
+	}
+	c.queue = append(c.queue, d)
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (s *Server) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// removeConnection deletes a connection, stops its worker and abandons its
+// queued deliveries. The caller must hold s.mu.
+func (s *Server) removeConnection(id uint64) {
+	c := s.connections[id]
+	delete(s.connections, id)
+	close(c.done)
+	for _, d := range c.queue {
+		s.finish(d.event, abandonedStatus(d))
+	}
+	c.queue = nil
+}
+
+// abandonedStatus is the outcome of a delivery that will not be attempted:
+// failed if an earlier attempt failed, otherwise none
+func abandonedStatus(d delivery) string {
+	if d.retry > 0 {
+		return "failed"
+	}
+	return ""
+}
+
+// finish records the outcome of one of an event's deliveries: "success",
+// "failed", or "" when it was abandoned without an attempt. The caller must hold s.mu.
+func (s *Server) finish(ev *event, status string) {
+	switch status {
+	case "success":
+		ev.delivered = true
+	case "failed":
+		ev.failed = true
+	}
+	ev.pending--
+	s.inflight--
+
+	if ev.pending == 0 {
+		switch {
+		case ev.failed:
+			ev.Status = "failed"
+		case ev.delivered:
+			ev.Status = "processed"
+		default:
+			ev.Status = "dropped"
+		}
+	}
+}
+
+// work delivers a connection's queue in order until the connection is removed
+// or the emulator is closed
+func (s *Server) work(c *connection) {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		var d delivery
+		ok := len(c.queue) > 0
+		if ok {
+			d, c.queue = c.queue[0], c.queue[1:]
+		}
+		s.mu.Unlock()
+
+		if !ok {
+			select {
+			case <-c.wake:
+				continue
+			case <-c.done:
+				return
+			case <-s.closed:
+				return
+			}
+		}
+
+		if !s.throttle(c) {
+			s.mu.Lock()
+			s.finish(d.event, abandonedStatus(d))
+			s.mu.Unlock()
+			return
+		}
+		s.deliver(c, d)
+	}
+}
+
+// throttle waits until the connection's EPS allows another delivery. It
+// returns false if the connection was removed or the emulator closed meanwhile.
+func (s *Server) throttle(c *connection) bool {
+	for {
+		s.mu.Lock()
+		wait := c.limiter.take(c.EPS, time.Now())
+		s.mu.Unlock()
+		if wait == 0 {
+			return true
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-c.done:
+			return false
+		case <-s.closed:
+			return false
+		}
+	}
+}
+
+// deliver attempts a queued delivery and schedules a retry on failure until
+// the connection's MaxRetries is exhausted
+func (s *Server) deliver(c *connection, d delivery) {
+	s.mu.Lock()
+	dest := s.destinations[c.DestinationID]
+	active := c.Status != "disabled" && dest != nil && dest.Status != "disabled"
+	if !active {
+		s.finish(d.event, abandonedStatus(d))
+	}
+	s.mu.Unlock()
+	if !active {
+		return
+	}
+
+	at := s.attempt(c, d.event)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if at.Status == "success" || d.retry >= c.MaxRetries {
+		s.finish(d.event, at.Status)
+		return
+	}
+
+	next := delivery{event: d.event, retry: d.retry + 1}
+	backoff := s.opts.RetryBackoff << d.retry
+	if c.Retry != nil {
+		backoff = c.Retry.Delay(d.retry)
+	}
+	time.AfterFunc(backoff, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.enqueue(c, next) {
+			s.finish(next.event, "failed")
+		}
+	})
+}
+
+// attempt sends an event through a connection once and records the attempt
+func (s *Server) attempt(c *connection, ev *event) *attempt {
+	s.mu.Lock()
+	var target *destination
+	if dest, ok := s.destinations[c.DestinationID]; ok {
+		copied := *dest
+		target = &copied
+	}
+	var credentials map[string]bool
+	if src, ok := s.sources[ev.SourceID]; ok {
+		credentials = src.credentialHeaders()
+	}
+	rule := c.rule
+	s.mu.Unlock()
+
+	body, contentType := ev.RawBody, ""
+	var err error
+	if rule != nil {
+		body, contentType, err = rule.Transform(ev.Event)
+	}
+
+	start := time.Now()
+	code, reason := 0, "destination not found"
+	switch {
+	case err != nil:
+		reason = err.Error()
+	case target != nil:
+		code, reason = s.post(target, ev, credentials, body, contentType)
+	}
+	duration := time.Since(start)
+
+	status := "success"
+	if reason != "" {
+		status = "failed"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := &attempt{
+		DeliveryAttempt: volley.DeliveryAttempt{
+			ID:           s.nextID("attempt"),
+			EventID:      ev.EventID,
+			ConnectionID: c.ID,
+			Status:       status,
+			StatusCode:   code,
+			ErrorReason:  reason,
+			DurationMs:   duration.Milliseconds(),
+			CreatedAt:    start.UTC(),
+		},
+		projectID:     ev.ProjectID,
+		sourceID:      ev.SourceID,
+		destinationID: c.DestinationID,
+	}
+	s.attempts = append(s.attempts, at)
+	return at
+}
+
+// post sends an event's body and original headers to a destination with its
+// method, static headers and credentials. The source's credential headers
+// are stripped, and a non-empty contentType replaces the original
+// Content-Type. It returns the response status code and, on failure, the
+// reason.
+func (s *Server) post(d *destination, ev *event, credentials map[string]bool, body, contentType string) (int, string) {
+	req, err := http.NewRequest(defaultString(d.Method, http.MethodPost), d.URL, strings.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	for name, value := range ev.Headers {
+		if hopHeaders[http.CanonicalHeaderKey(name)] || credentials[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			req.Header.Set(name, v)
+		case []interface{}:
+			for _, item := range v {
+				req.Header.Add(name, fmt.Sprint(item))
+			}
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range d.Headers {
+		req.Header.Set(name, value)
+	}
+	switch d.AuthType {
+	case "basic":
+		req.SetBasicAuth(d.AuthUsername, d.authPassword)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+d.authToken)
+	}
+	req.Header.Set("X-Volley-Event-ID", ev.EventID)
+
+	resp, err := s.client(d).Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("destination responded with HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, ""
+}
@@ -0,0 +1,30 @@
+package emulator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	var l limiter
+	now := time.Unix(0, 0)
+
+	// A full bucket allows a burst of eps
+	for i := 0; i < 2; i++ {
+		if wait := l.take(2, now); wait != 0 {
+			t.Fatalf("take %d: expected no wait, got %s", i, wait)
+		}
+	}
+	if wait := l.take(2, now); wait != 500*time.Millisecond {
+		t.Errorf("Expected to wait 500ms for the next token, got %s", wait)
+	}
+
+	// Tokens refill at eps per second
+	if wait := l.take(2, now.Add(500*time.Millisecond)); wait != 0 {
+		t.Errorf("Expected a token after 500ms, got wait %s", wait)
+	}
+
+	if wait := l.take(0, now); wait != 0 {
+		t.Errorf("Expected eps 0 to be unlimited, got wait %s", wait)
+	}
+}
@@ -0,0 +1,130 @@
+package emulator
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// maxDestinationTimeout is the longest per-destination delivery timeout
+const maxDestinationTimeout = 120
+
+func validMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+func validDestinationAuthType(authType string) bool {
+	return authType == "none" || authType == "bearer" || authType == "basic"
+}
+
+// checkDestinationOptions validates the request options shared by create and
+// update and returns an error message, or "" if they are valid
+func checkDestinationOptions(method string, timeout *int, authType string, headers map[string]string) string {
+	if method != "" && !validMethod(method) {
+		return fmt.Sprintf("invalid method %q: must be POST, PUT or PATCH", method)
+	}
+	if timeout != nil && (*timeout < 0 || *timeout > maxDestinationTimeout) {
+		return fmt.Sprintf("timeout_seconds must be between 0 and %d", maxDestinationTimeout)
+	}
+	if authType != "" && !validDestinationAuthType(authType) {
+		return fmt.Sprintf("invalid auth_type %q", authType)
+	}
+	for name := range headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Sprintf("invalid header name %q", name)
+		}
+		if hopHeaders[http.CanonicalHeaderKey(name)] || http.CanonicalHeaderKey(name) == "Authorization" {
+			return fmt.Sprintf("header %q cannot be set", name)
+		}
+	}
+	return ""
+}
+
+// setAuth sets the credentials used by the destination's auth type and
+// clears the rest. It returns an error message if a required one is missing.
+func (d *destination) setAuth(username, password, token string) string {
+	switch d.AuthType {
+	case "basic":
+		if username == "" || password == "" {
+			return "auth_username and auth_password are required for basic auth"
+		}
+	case "bearer":
+		if token == "" {
+			return "auth_token is required for bearer auth"
+		}
+	}
+	d.AuthUsername, d.authPassword, d.authToken = "", "", ""
+	switch d.AuthType {
+	case "basic":
+		d.AuthUsername, d.authPassword = username, password
+	case "bearer":
+		d.authToken = token
+	}
+	return ""
+}
+
+// setClientCert parses a PEM certificate and key and prepares a transport
+// presenting them. Both empty removes the client certificate.
+func (s *Server) setClientCert(d *destination, cert, key string) string {
+	if (cert == "") != (key == "") {
+		return "client_cert and client_key must be set together"
+	}
+	var transport *http.Transport
+	if cert != "" {
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return "invalid client certificate: " + err.Error()
+		}
+		base, ok := s.opts.HTTPClient.Transport.(*http.Transport)
+		if s.opts.HTTPClient.Transport == nil {
+			base, ok = http.DefaultTransport.(*http.Transport), true
+		}
+		if !ok {
+			return "the emulator's HTTP client does not support client certificates"
+		}
+		transport = base.Clone()
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{pair}
+	}
+	if d.transport != nil {
+		d.transport.CloseIdleConnections()
+	}
+	d.transport = transport
+	d.ClientCertSet = transport != nil
+	return ""
+}
+
+// view returns the destination as served by the API, with secrets reduced to
+// whether they are set
+func (d *destination) view() volley.Destination {
+	v := d.Destination
+	v.AuthSecretSet = d.authPassword != "" || d.authToken != ""
+	if len(d.Headers) > 0 {
+		v.Headers = make(map[string]string, len(d.Headers))
+		for name, value := range d.Headers {
+			v.Headers[name] = value
+		}
+	}
+	return v
+}
+
+// client returns the HTTP client used for deliveries to the destination
+func (s *Server) client(d *destination) *http.Client {
+	if d.transport == nil && d.TimeoutSeconds == 0 {
+		return s.opts.HTTPClient
+	}
+	c := *s.opts.HTTPClient
+	if d.transport != nil {
+		c.Transport = d.transport
+	}
+	if d.TimeoutSeconds > 0 {
+		c.Timeout = time.Duration(d.TimeoutSeconds) * time.Second
+	}
+	return &c
+}
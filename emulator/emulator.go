@@ -0,0 +1,395 @@
+// Package emulator provides an in-memory Volley server for offline development
+// and tests.
+//
+// The emulator accepts webhooks on /hook/{ingestion_id}, stores them as events
+// and delivers them to destination URLs through the project's connections,
+// honoring each connection's EPS and MaxRetries and recording a delivery
+// attempt for every try. It also serves the /api management endpoints used by
+// the SDK, so an unmodified volley.Client pointed at it with volley.WithBaseURL
+// behaves as it would against production:
+//
+//	emu := emulator.New(nil)
+//	defer emu.Close()
+//
+//	server := httptest.NewServer(emu)
+//	defer server.Close()
+//
+//	client := volley.NewClient("any-token", volley.WithBaseURL(server.URL))
+//
+// A default organization and project (both with ID 1) exist from the start.
+// Every request acts as a single user, who owns the organizations it creates.
+// GET responses carry an ETag and honor If-None-Match. Webhooks must pass their
+// source's signature check and basic or API key auth once those secrets are set.
+// Deliveries use each destination's method, static headers, credentials,
+// timeout and client certificate. Connections only deliver events that pass
+// their filters, transform bodies as the rules package does and back off
+// retries with their retry strategy.
+package emulator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/rules"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry of a failed delivery
+	DefaultRetryBackoff = time.Second
+	// DefaultDeliveryTimeout bounds each delivery request
+	DefaultDeliveryTimeout = 10 * time.Second
+	// DefaultPageSize is the page size of list endpoints when no limit is given
+	DefaultPageSize = 50
+)
+
+// Options configures an emulator
+type Options struct {
+	// Token is the only API token accepted when set, besides tokens created
+	// through the API; otherwise any bearer token that was not revoked is accepted
+	Token string
+	// HTTPClient is used for deliveries (default: a client with DefaultDeliveryTimeout)
+	HTTPClient *http.Client
+	// RetryBackoff is the delay before the first retry of a failed delivery,
+	// doubling after each attempt (default: DefaultRetryBackoff). Connections
+	// with a retry strategy use it instead.
+	RetryBackoff time.Duration
+}
+
+// Server is an in-memory Volley API. It implements http.Handler.
+type Server struct {
+	opts Options
+
+	mu           sync.Mutex
+	ids          map[string]uint64
+	orgs         map[uint64]*volley.Organization
+	members      map[uint64]*volley.Member
+	invites      map[uint64]*volley.Invite
+	tokens       map[uint64]*apiToken
+	revoked      map[string]bool
+	projects     map[uint64]*volley.Project
+	sources      map[uint64]*source
+	destinations map[uint64]*destination
+	connections  map[uint64]*connection
+	events       []*event
+	attempts     []*attempt
+	// inflight counts deliveries that are queued, running or waiting for a retry
+	inflight int
+
+	wg        sync.WaitGroup
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+type source struct {
+	volley.Source
+	projectID uint64
+	limiter   limiter
+
+	// Write-only credentials, checked on ingestion
+	webhookSecret   string
+	previousSecret  string // accepted until previousExpires after a rotation
+	previousExpires time.Time
+	authPassword    string
+	authKey         string
+}
+
+type destination struct {
+	volley.Destination
+	projectID uint64
+
+	// Write-only credentials, sent with deliveries
+	authPassword string
+	authToken    string
+	// transport presents the client certificate, if one is set
+	transport *http.Transport
+}
+
+type connection struct {
+	volley.Connection
+	projectID uint64
+	limiter   limiter
+	queue     []delivery
+	wake      chan struct{}
+	done      chan struct{}
+
+	// rule evaluates the connection's filters and transform; nil if it has neither
+	rule *rules.Rule
+}
+
+type event struct {
+	volley.Event
+	// pending counts deliveries that have not finished yet
+	pending   int
+	delivered bool
+	failed    bool
+}
+
+type attempt struct {
+	volley.DeliveryAttempt
+	projectID     uint64
+	sourceID      uint64
+	destinationID uint64
+}
+
+// New creates an emulator with a default organization and project
+func New(opts *Options) *Server {
+	s := &Server{
+		ids:          make(map[string]uint64),
+		orgs:         make(map[uint64]*volley.Organization),
+		members:      make(map[uint64]*volley.Member),
+		invites:      make(map[uint64]*volley.Invite),
+		tokens:       make(map[uint64]*apiToken),
+		revoked:      make(map[string]bool),
+		projects:     make(map[uint64]*volley.Project),
+		sources:      make(map[uint64]*source),
+		destinations: make(map[uint64]*destination),
+		connections:  make(map[uint64]*connection),
+		closed:       make(chan struct{}),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.HTTPClient == nil {
+		s.opts.HTTPClient = &http.Client{Timeout: DefaultDeliveryTimeout}
+	}
+	if s.opts.RetryBackoff <= 0 {
+		s.opts.RetryBackoff = DefaultRetryBackoff
+	}
+
+	now := time.Now().UTC()
+	org := &volley.Organization{ID: s.nextID("org"), Name: "Default Organization", Slug: "default", AccountID: 1, Role: string(volley.RoleOwner), CreatedAt: now}
+	s.orgs[org.ID] = org
+	s.addMember(org.ID, callerUserID, callerEmail, volley.RoleOwner)
+	project := &volley.Project{ID: s.nextID("project"), Name: "Default Project", OrganizationID: org.ID, IsDefault: true, CreatedAt: now, UpdatedAt: now}
+	s.projects[project.ID] = project
+
+	return s
+}
+
+// Close stops delivery workers. Queued deliveries are abandoned.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.wg.Wait()
+}
+
+// ServeHTTP routes webhook ingestion and API requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] == "hook":
+		s.ingest(w, r, parts[1])
+	case len(parts) > 1 && parts[0] == "api":
+		if !s.authorize(w, r, parts[1:]) {
+			return
+		}
+		s.route(w, r, parts[1:])
+	default:
+		replyError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// route is an API endpoint. Pattern segments of ":id" match a numeric ID,
+// which is passed to the handler.
+type route struct {
+	method  string
+	pattern string
+	handle  func(w http.ResponseWriter, r *http.Request, id uint64)
+}
+
+func (s *Server) routes() []route {
+	return []route{
+		{http.MethodGet, "org/list", s.listOrganizations},
+		{http.MethodGet, "org", s.getOrganization},
+		{http.MethodPost, "org", s.createOrganization},
+		{http.MethodPut, "org/:id", s.updateOrganization},
+		{http.MethodDelete, "org/:id", s.deleteOrganization},
+		{http.MethodGet, "org/:id/members", s.listMembers},
+		{http.MethodDelete, "org/:id/membership", s.leaveOrganization},
+		{http.MethodGet, "org/:id/invites", s.listInvites},
+		{http.MethodPost, "org/:id/invites", s.inviteMember},
+		{http.MethodPut, "members/:id", s.updateMember},
+		{http.MethodDelete, "members/:id", s.removeMember},
+		{http.MethodDelete, "invites/:id", s.revokeInvite},
+		{http.MethodGet, "tokens", s.listTokens},
+		{http.MethodPost, "tokens", s.createToken},
+		{http.MethodGet, "tokens/current", s.currentToken},
+		{http.MethodDelete, "tokens/:id", s.revokeToken},
+		{http.MethodGet, "projects", s.listProjects},
+		{http.MethodPost, "projects", s.createProject},
+		{http.MethodPut, "projects/:id", s.updateProject},
+		{http.MethodDelete, "projects/:id", s.deleteProject},
+		{http.MethodGet, "projects/:id/sources", s.listSources},
+		{http.MethodPost, "projects/:id/sources", s.createSource},
+		{http.MethodGet, "projects/:id/destinations", s.listDestinations},
+		{http.MethodPost, "projects/:id/destinations", s.createDestination},
+		{http.MethodGet, "projects/:id/connections", s.listConnections},
+		{http.MethodPost, "projects/:id/connections", s.createConnection},
+		{http.MethodGet, "projects/:id/requests", s.listEvents},
+		{http.MethodGet, "projects/:id/delivery-attempts", s.listDeliveryAttempts},
+		{http.MethodGet, "sources/:id", s.getSource},
+		{http.MethodPut, "sources/:id", s.updateSource},
+		{http.MethodDelete, "sources/:id", s.deleteSource},
+		{http.MethodPost, "sources/:id/rotate-secret", s.rotateSourceSecret},
+		{http.MethodPut, "sources/:id/auth", s.setSourceAuth},
+		{http.MethodGet, "destinations/:id", s.getDestination},
+		{http.MethodPut, "destinations/:id", s.updateDestination},
+		{http.MethodDelete, "destinations/:id", s.deleteDestination},
+		{http.MethodGet, "connections/:id", s.getConnection},
+		{http.MethodPut, "connections/:id", s.updateConnection},
+		{http.MethodDelete, "connections/:id", s.deleteConnection},
+		{http.MethodGet, "requests/:id", s.getEvent},
+		{http.MethodPost, "replay-event", s.replayEvent},
+	}
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, parts []string) {
+	pathMatched := false
+	for _, rt := range s.routes() {
+		id, ok := match(rt.pattern, parts)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != r.Method {
+			continue
+		}
+		if r.Method == http.MethodGet {
+			conditional(w, r, func(w http.ResponseWriter) { rt.handle(w, r, id) })
+		} else {
+			rt.handle(w, r, id)
+		}
+		return
+	}
+
+	if pathMatched {
+		replyError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	replyError(w, http.StatusNotFound, "not found")
+}
+
+func match(pattern string, parts []string) (uint64, bool) {
+	segments := strings.Split(pattern, "/")
+	if len(segments) != len(parts) {
+		return 0, false
+	}
+
+	var id uint64
+	for i, seg := range segments {
+		if seg == ":id" {
+			n, err := strconv.ParseUint(parts[i], 10, 64)
+			if err != nil {
+				return 0, false
+			}
+			id = n
+			continue
+		}
+		if seg != parts[i] {
+			return 0, false
+		}
+	}
+	return id, true
+}
+
+// conditional runs a GET handler with its response buffered, tags a
+// successful response with an ETag of its body and answers 304 Not Modified
+// when it matches the request's If-None-Match
+func conditional(w http.ResponseWriter, r *http.Request, handle func(w http.ResponseWriter)) {
+	buf := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+	handle(buf)
+
+	if buf.status == http.StatusOK {
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:12]) + `"`
+		buf.header.Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	for name, values := range buf.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(buf.status)
+	w.Write(buf.body.Bytes())
+}
+
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+
+// nextID returns the next ID in a per-kind sequence. The caller must hold s.mu.
+func (s *Server) nextID(kind string) uint64 {
+	s.ids[kind]++
+	return s.ids[kind]
+}
+
+func reply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func replyError(w http.ResponseWriter, status int, msg string) {
+	reply(w, status, map[string]string{"error": msg})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		replyError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+// slugify lowercases name and replaces runs of other characters with dashes
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func sortedIDs[T any](m map[uint64]T) []uint64 {
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
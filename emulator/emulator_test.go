@@ -0,0 +1,311 @@
+package emulator_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// receiver is a destination recording the webhooks it receives
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	bodies   []string
+	eventIDs []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	rc.bodies = append(rc.bodies, string(body))
+	rc.eventIDs = append(rc.eventIDs, r.Header.Get("X-Volley-Event-ID"))
+	if rc.status == 0 {
+		rc.status = http.StatusOK
+	}
+	w.WriteHeader(rc.status)
+}
+
+func (rc *receiver) setStatus(status int) {
+	rc.mu.Lock()
+	rc.status = status
+	rc.mu.Unlock()
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.bodies)
+}
+
+func setupEmulator(t *testing.T, opts *emulator.Options) (*emulator.Server, *volley.Client) {
+	t.Helper()
+	emu := emulator.New(opts)
+	server := httptest.NewServer(emu)
+	t.Cleanup(func() {
+		server.Close()
+		emu.Close()
+	})
+	return emu, volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+}
+
+// pipeline creates a source connected to a destination at url in the default project
+func pipeline(t *testing.T, client *volley.Client, url string, maxRetries int) (*volley.Source, *volley.Connection) {
+	t.Helper()
+	src, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+	dest, err := client.CreateDestination(1, volley.CreateDestinationRequest{Name: "local", URL: url})
+	if err != nil {
+		t.Fatalf("CreateDestination failed: %v", err)
+	}
+	conn, err := client.CreateConnection(1, volley.CreateConnectionRequest{
+		SourceID:      src.ID,
+		DestinationID: dest.ID,
+		MaxRetries:    maxRetries,
+	})
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	return src, conn
+}
+
+func waitIdle(t *testing.T, emu *emulator.Server) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := emu.WaitIdle(ctx); err != nil {
+		t.Fatalf("deliveries did not finish: %v", err)
+	}
+}
+
+func TestManagementAPI(t *testing.T) {
+	_, client := setupEmulator(t, nil)
+
+	org, err := client.GetOrganization(nil)
+	if err != nil {
+		t.Fatalf("GetOrganization failed: %v", err)
+	}
+	projects, err := client.ListProjects()
+	if err != nil {
+		t.Fatalf("ListProjects failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0].OrganizationID != org.ID || !projects[0].IsDefault {
+		t.Fatalf("Expected the default project, got %+v", projects)
+	}
+
+	created, err := client.CreateOrganization(volley.CreateOrganizationRequest{Name: "Staging Team"})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	client.SetOrganizationID(created.ID)
+	if projects, _ := client.ListProjects(); len(projects) != 0 {
+		t.Errorf("Expected no projects in the new organization, got %d", len(projects))
+	}
+	client.ClearOrganizationID()
+
+	spec := &volley.TopologySpec{
+		Sources:      []volley.SourceSpec{{Slug: "stripe", EPS: 10}},
+		Destinations: []volley.DestinationSpec{{Name: "api", URL: "https://api.example.com/hooks"}},
+		Connections:  []volley.ConnectionSpec{{Source: "stripe", Destination: "api", MaxRetries: 3}},
+	}
+	plan, err := client.PlanTopology(1, spec, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if _, err := client.ApplyTopology(plan); err != nil {
+		t.Fatalf("ApplyTopology failed: %v", err)
+	}
+	plan, err = client.PlanTopology(1, spec, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected no changes after apply, got:\n%s", plan)
+	}
+
+	sources, _ := client.ListSources(1)
+	if len(sources) != 1 || sources[0].ConnectionCount != 1 || sources[0].IngestionID == "" {
+		t.Fatalf("Expected one connected source, got %+v", sources)
+	}
+	if err := client.DeleteSource(sources[0].ID); err != nil {
+		t.Fatalf("DeleteSource failed: %v", err)
+	}
+	if conns, _ := client.GetConnections(1); len(conns) != 0 {
+		t.Errorf("Expected deleting the source to delete its connection, got %d", len(conns))
+	}
+
+	_, err = client.GetSource(sources[0].ID)
+	var apiErr *volley.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorMsg != "source not found" {
+		t.Errorf("Expected a not found API error, got %v", err)
+	}
+}
+
+func TestIngestAndDeliver(t *testing.T) {
+	dest := &receiver{}
+	local := httptest.NewServer(dest)
+	defer local.Close()
+
+	emu, client := setupEmulator(t, nil)
+	src, conn := pipeline(t, client, local.URL, 0)
+
+	eventID, err := client.SendWebhook(src.IngestionID, map[string]string{"type": "charge.succeeded"})
+	if err != nil {
+		t.Fatalf("SendWebhook failed: %v", err)
+	}
+	waitIdle(t, emu)
+
+	if dest.count() != 1 || dest.bodies[0] != `{"type":"charge.succeeded"}` || dest.eventIDs[0] != eventID {
+		t.Fatalf("Expected the raw body to be delivered once, got %v %v", dest.bodies, dest.eventIDs)
+	}
+
+	events, err := client.ListEvents(1, nil)
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if events.Total != 1 || events.Requests[0].EventID != eventID || events.Requests[0].Status != "processed" {
+		t.Fatalf("Expected one processed event, got %+v", events.Requests)
+	}
+
+	event, err := client.GetEvent(events.Requests[0].ID)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if len(event.DeliveryAttempts) != 1 || event.DeliveryAttempts[0].ConnectionID != conn.ID ||
+		event.DeliveryAttempts[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected one successful attempt, got %+v", event.DeliveryAttempts)
+	}
+}
+
+func TestDeliveryRetriesAndReplay(t *testing.T) {
+	dest := &receiver{status: http.StatusInternalServerError}
+	local := httptest.NewServer(dest)
+	defer local.Close()
+
+	emu, client := setupEmulator(t, &emulator.Options{RetryBackoff: time.Millisecond})
+	src, _ := pipeline(t, client, local.URL, 2)
+
+	eventID, err := client.SendWebhook(src.IngestionID, map[string]int{"n": 1})
+	if err != nil {
+		t.Fatalf("SendWebhook failed: %v", err)
+	}
+	waitIdle(t, emu)
+
+	attempts, err := client.ListDeliveryAttempts(1, &volley.ListDeliveryAttemptsOptions{EventID: eventID, Sort: "time_oldest"})
+	if err != nil {
+		t.Fatalf("ListDeliveryAttempts failed: %v", err)
+	}
+	if len(attempts.Attempts) != 3 {
+		t.Fatalf("Expected 1 attempt and 2 retries, got %d", len(attempts.Attempts))
+	}
+	for _, at := range attempts.Attempts {
+		if at.Status != "failed" || at.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected failed attempt with status 500, got %+v", at)
+		}
+	}
+
+	failed, _ := client.ListEvents(1, &volley.ListEventsOptions{Status: "failed"})
+	if failed.Total != 1 {
+		t.Fatalf("Expected the event to be failed, got %d failed events", failed.Total)
+	}
+
+	dest.setStatus(http.StatusOK)
+	result, err := client.ReplayEvent(volley.ReplayEventRequest{EventID: eventID})
+	if err != nil {
+		t.Fatalf("ReplayEvent failed: %v", err)
+	}
+	if !result.Success || result.StatusCode != http.StatusOK || result.AttemptID == 0 {
+		t.Errorf("Expected a successful replay, got %+v", result)
+	}
+	if processed, _ := client.ListEvents(1, &volley.ListEventsOptions{Status: "processed"}); processed.Total != 1 {
+		t.Errorf("Expected the replayed event to be processed, got %d", processed.Total)
+	}
+}
+
+func TestIngestRateLimit(t *testing.T) {
+	_, client := setupEmulator(t, nil)
+	src, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "github", EPS: 1})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+
+	if _, err := client.SendWebhook(src.IngestionID, map[string]int{"n": 1}); err != nil {
+		t.Fatalf("first webhook failed: %v", err)
+	}
+	if _, err := client.SendWebhook(src.IngestionID, map[string]int{"n": 2}); err == nil {
+		t.Error("Expected the second webhook within a second to be rate limited")
+	}
+
+	// Without connections events are stored but dropped
+	events, _ := client.ListEvents(1, nil)
+	if events.Total != 1 || events.Requests[0].Status != "dropped" {
+		t.Errorf("Expected one dropped event, got %+v", events.Requests)
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	emu := emulator.New(&emulator.Options{Token: "secret"})
+	defer emu.Close()
+	server := httptest.NewServer(emu)
+	defer server.Close()
+
+	if _, err := volley.NewClient("wrong", volley.WithBaseURL(server.URL)).ListProjects(); err == nil {
+		t.Error("Expected a wrong token to be rejected")
+	}
+	if _, err := volley.NewClient("secret", volley.WithBaseURL(server.URL)).ListProjects(); err != nil {
+		t.Errorf("Expected the configured token to be accepted, got %v", err)
+	}
+}
+
+func TestConnectionRulesDelivery(t *testing.T) {
+	dest := &receiver{}
+	local := httptest.NewServer(dest)
+	defer local.Close()
+
+	emu, client := setupEmulator(t, nil)
+	src, conn := pipeline(t, client, local.URL, 0)
+	if _, err := client.Connections.Update(conn.ID, volley.UpdateConnectionRequest{
+		Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpPrefix, Value: "charge."}},
+		Transform: &volley.Transform{Type: volley.TransformMap, Mappings: []volley.FieldMapping{
+			{Target: volley.TargetBody, Path: "data.id", To: "charge"},
+		}},
+	}); err != nil {
+		t.Fatalf("Connections.Update failed: %v", err)
+	}
+
+	for _, payload := range []string{`{"type":"customer.created"}`, `{"type":"charge.succeeded","data":{"id":"ch_1"}}`, `not json`} {
+		if code := post(t, client.Ingestion.URL(src.IngestionID), payload); code != http.StatusAccepted {
+			t.Fatalf("Expected the webhook to be accepted, got %d", code)
+		}
+	}
+	waitIdle(t, emu)
+
+	if dest.count() != 1 || dest.bodies[0] != `{"charge":"ch_1"}` {
+		t.Fatalf("Expected only the charge to be delivered, transformed, got %v", dest.bodies)
+	}
+	events, err := client.Events.List(1, &volley.ListEventsOptions{Status: "dropped"})
+	if err != nil || events.Total != 2 {
+		t.Errorf("Expected the filtered events to be dropped, got %v (%v)", events, err)
+	}
+}
+
+func post(t *testing.T, url, body string) int {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
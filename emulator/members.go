@@ -0,0 +1,265 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// The emulator has a single user, who makes every API request
+const (
+	callerUserID = 1
+	callerEmail  = "you@example.com"
+)
+
+// inviteTTL is how long an invite stays pending
+const inviteTTL = 7 * 24 * time.Hour
+
+// roleRank orders roles from most to least privileged
+var roleRank = map[volley.Role]int{
+	volley.RoleOwner:  0,
+	volley.RoleAdmin:  1,
+	volley.RoleMember: 2,
+	volley.RoleViewer: 3,
+}
+
+func (s *Server) addMember(orgID, userID uint64, email string, role volley.Role) *volley.Member {
+	m := &volley.Member{
+		ID:             s.nextID("member"),
+		OrganizationID: orgID,
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.members[m.ID] = m
+	return m
+}
+
+// callerMember returns the caller's membership in an organization, or nil
+func (s *Server) callerMember(orgID uint64) *volley.Member {
+	for _, m := range s.members {
+		if m.OrganizationID == orgID && m.UserID == callerUserID {
+			return m
+		}
+	}
+	return nil
+}
+
+// orgView returns the API representation of an organization, with the caller's role
+func (s *Server) orgView(org *volley.Organization) volley.Organization {
+	view := *org
+	if m := s.callerMember(org.ID); m != nil {
+		view.Role = string(m.Role)
+	}
+	return view
+}
+
+func (s *Server) organization(w http.ResponseWriter, id uint64) (*volley.Organization, bool) {
+	org, ok := s.orgs[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "organization not found")
+	}
+	return org, ok
+}
+
+// requireRole replies 403 unless the caller has at least role in the organization
+func (s *Server) requireRole(w http.ResponseWriter, orgID uint64, role volley.Role) bool {
+	m := s.callerMember(orgID)
+	if m == nil || roleRank[m.Role] > roleRank[role] {
+		replyError(w, http.StatusForbidden, "requires the "+string(role)+" role")
+		return false
+	}
+	return true
+}
+
+// lastOwner reports whether m is the only owner of its organization
+func (s *Server) lastOwner(m *volley.Member) bool {
+	if m.Role != volley.RoleOwner {
+		return false
+	}
+	for _, other := range s.members {
+		if other.ID != m.ID && other.OrganizationID == m.OrganizationID && other.Role == volley.RoleOwner {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request, orgID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.organization(w, orgID); !ok || !s.requireRole(w, orgID, volley.RoleViewer) {
+		return
+	}
+	members := []volley.Member{}
+	for _, id := range sortedIDs(s.members) {
+		if m := s.members[id]; m.OrganizationID == orgID {
+			members = append(members, *m)
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"members": members})
+}
+
+func (s *Server) updateMember(w http.ResponseWriter, r *http.Request, id uint64) {
+	var req struct {
+		Role volley.Role `json:"role"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if !req.Role.Valid() {
+		replyError(w, http.StatusBadRequest, "invalid role")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.members[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "member not found")
+		return
+	}
+	// Only owners grant or revoke ownership
+	required := volley.RoleAdmin
+	if m.Role == volley.RoleOwner || req.Role == volley.RoleOwner {
+		required = volley.RoleOwner
+	}
+	if !s.requireRole(w, m.OrganizationID, required) {
+		return
+	}
+	if req.Role != volley.RoleOwner && s.lastOwner(m) {
+		replyError(w, http.StatusBadRequest, "an organization must keep at least one owner")
+		return
+	}
+	m.Role = req.Role
+	reply(w, http.StatusOK, map[string]interface{}{"member": m})
+}
+
+func (s *Server) removeMember(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.members[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "member not found")
+		return
+	}
+	if m.UserID != callerUserID && !s.requireRole(w, m.OrganizationID, volley.RoleAdmin) {
+		return
+	}
+	if s.lastOwner(m) {
+		replyError(w, http.StatusBadRequest, "an organization must keep at least one owner")
+		return
+	}
+	delete(s.members, id)
+	reply(w, http.StatusOK, map[string]string{"message": "member removed"})
+}
+
+func (s *Server) leaveOrganization(w http.ResponseWriter, r *http.Request, orgID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.organization(w, orgID); !ok {
+		return
+	}
+	m := s.callerMember(orgID)
+	if m == nil {
+		replyError(w, http.StatusNotFound, "not a member of this organization")
+		return
+	}
+	if s.lastOwner(m) {
+		replyError(w, http.StatusBadRequest, "the last owner cannot leave an organization")
+		return
+	}
+	delete(s.members, m.ID)
+	reply(w, http.StatusOK, map[string]string{"message": "left organization"})
+}
+
+func (s *Server) listInvites(w http.ResponseWriter, r *http.Request, orgID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.organization(w, orgID); !ok || !s.requireRole(w, orgID, volley.RoleAdmin) {
+		return
+	}
+	invites := []volley.Invite{}
+	for _, id := range sortedIDs(s.invites) {
+		if inv := s.invites[id]; inv.OrganizationID == orgID {
+			invites = append(invites, *inv)
+		}
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"invites": invites})
+}
+
+func (s *Server) inviteMember(w http.ResponseWriter, r *http.Request, orgID uint64) {
+	var req volley.InviteMemberRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !strings.Contains(email, "@") {
+		replyError(w, http.StatusBadRequest, "a valid email is required")
+		return
+	}
+	if !req.Role.Valid() {
+		replyError(w, http.StatusBadRequest, "invalid role")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	required := volley.RoleAdmin
+	if req.Role == volley.RoleOwner {
+		required = volley.RoleOwner
+	}
+	if _, ok := s.organization(w, orgID); !ok || !s.requireRole(w, orgID, required) {
+		return
+	}
+	for _, m := range s.members {
+		if m.OrganizationID == orgID && m.Email == email {
+			replyError(w, http.StatusConflict, "already a member")
+			return
+		}
+	}
+	for _, inv := range s.invites {
+		if inv.OrganizationID == orgID && inv.Email == email {
+			replyError(w, http.StatusConflict, "already invited")
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	inv := &volley.Invite{
+		ID:             s.nextID("invite"),
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           req.Role,
+		InvitedBy:      callerUserID,
+		ExpiresAt:      now.Add(inviteTTL),
+		CreatedAt:      now,
+	}
+	s.invites[inv.ID] = inv
+	reply(w, http.StatusCreated, map[string]interface{}{"invite": inv})
+}
+
+func (s *Server) revokeInvite(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inv, ok := s.invites[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "invite not found")
+		return
+	}
+	if !s.requireRole(w, inv.OrganizationID, volley.RoleAdmin) {
+		return
+	}
+	delete(s.invites, id)
+	reply(w, http.StatusOK, map[string]string{"message": "invite revoked"})
+}
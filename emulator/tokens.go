@@ -0,0 +1,182 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// tokenPrefixLen is how much of a secret APIToken.Prefix reveals
+const tokenPrefixLen = 8
+
+type apiToken struct {
+	volley.APIToken
+	secret string
+}
+
+// bearer returns the request's bearer token
+func bearer(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// authorize checks the request's token and its scopes, replying 401 or 403
+// on failure. Tokens created through the API carry scopes and may expire;
+// the bootstrap token (see Options.Token) may do anything.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, parts []string) bool {
+	secret := bearer(r)
+	if secret == "" {
+		replyError(w, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tokenBySecret(secret)
+	if t == nil {
+		if s.revoked[secret] || (s.opts.Token != "" && secret != s.opts.Token) {
+			replyError(w, http.StatusUnauthorized, "unauthorized")
+			return false
+		}
+		return true
+	}
+
+	now := time.Now().UTC()
+	if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+		replyError(w, http.StatusUnauthorized, "token expired")
+		return false
+	}
+	if scope := requiredScope(r.Method, parts); !allows(t, scope) {
+		replyError(w, http.StatusForbidden, "token lacks the "+string(scope)+" scope")
+		return false
+	}
+	t.LastUsedAt = &now
+	return true
+}
+
+// requiredScope returns the scope an API request needs
+func requiredScope(method string, parts []string) volley.Scope {
+	switch parts[0] {
+	case "tokens", "members", "invites":
+		if parts[len(parts)-1] == "current" {
+			return volley.ScopeRead
+		}
+		return volley.ScopeAdmin
+	case "org":
+		if method != http.MethodGet && len(parts) > 1 {
+			return volley.ScopeAdmin
+		}
+	}
+	if method == http.MethodGet {
+		return volley.ScopeRead
+	}
+	return volley.ScopeWrite
+}
+
+// allows reports whether t grants scope; admin implies write, which implies read
+func allows(t *apiToken, scope volley.Scope) bool {
+	switch {
+	case t.HasScope(volley.ScopeAdmin):
+		return true
+	case t.HasScope(volley.ScopeWrite):
+		return scope != volley.ScopeAdmin
+	}
+	return scope == volley.ScopeRead && t.HasScope(volley.ScopeRead)
+}
+
+func (s *Server) tokenBySecret(secret string) *apiToken {
+	for _, t := range s.tokens {
+		if t.secret == secret {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) listTokens(w http.ResponseWriter, r *http.Request, _ uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := []volley.APIToken{}
+	for _, id := range sortedIDs(s.tokens) {
+		tokens = append(tokens, s.tokens[id].APIToken)
+	}
+	reply(w, http.StatusOK, map[string]interface{}{"tokens": tokens})
+}
+
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request, _ uint64) {
+	var req volley.CreateTokenRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		replyError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		replyError(w, http.StatusBadRequest, "at least one scope is required")
+		return
+	}
+	for _, scope := range req.Scopes {
+		if scope != volley.ScopeRead && scope != volley.ScopeWrite && scope != volley.ScopeAdmin {
+			replyError(w, http.StatusBadRequest, "invalid scope "+string(scope))
+			return
+		}
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		replyError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret := "vly_" + randomHex(24)
+	t := &apiToken{
+		APIToken: volley.APIToken{
+			ID:        s.nextID("token"),
+			Name:      req.Name,
+			Prefix:    secret[:tokenPrefixLen],
+			Scopes:    req.Scopes,
+			ExpiresAt: req.ExpiresAt,
+			CreatedAt: now,
+		},
+		secret: secret,
+	}
+	s.tokens[t.ID] = t
+	reply(w, http.StatusCreated, map[string]interface{}{"token": volley.NewAPIToken{APIToken: t.APIToken, Token: secret}})
+}
+
+func (s *Server) revokeToken(w http.ResponseWriter, r *http.Request, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		replyError(w, http.StatusNotFound, "token not found")
+		return
+	}
+	delete(s.tokens, id)
+	s.revoked[t.secret] = true
+	reply(w, http.StatusOK, map[string]string{"message": "token revoked"})
+}
+
+func (s *Server) currentToken(w http.ResponseWriter, r *http.Request, _ uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := volley.TokenInfo{UserID: callerUserID, Email: callerEmail}
+	if t := s.tokenBySecret(bearer(r)); t != nil {
+		info.Token = t.APIToken
+	} else {
+		// The bootstrap token is not listed and has every scope
+		info.Token = volley.APIToken{
+			Name:   "bootstrap",
+			Scopes: []volley.Scope{volley.ScopeRead, volley.ScopeWrite, volley.ScopeAdmin},
+		}
+	}
+	reply(w, http.StatusOK, info)
+}
@@ -19,8 +19,11 @@ type ListEventsOptions struct {
 	Offset        *int
 }
 
-// ListEvents lists all events/requests for a project with optional filters
-func (c *Client) ListEvents(projectID uint64, opts *ListEventsOptions) (*ListEventsResponse, error) {
+// EventsService lists, gets and replays received events
+type EventsService service
+
+// List lists all events/requests for a project with optional filters
+func (s *EventsService) List(projectID uint64, opts *ListEventsOptions) (*ListEventsResponse, error) {
 	path := fmt.Sprintf("/api/projects/%d/requests", projectID)
 
 	params := make(map[string]string)
@@ -54,23 +57,23 @@ func (c *Client) ListEvents(projectID uint64, opts *ListEventsOptions) (*ListEve
 		}
 	}
 
-	resp, err := c.doRequest("GET", path, nil, params)
+	resp, err := s.client.doRequest("GET", path, nil, params)
 	if err != nil {
 		return nil, err
 	}
 
 	var result ListEventsResponse
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result, nil
 }
 
-// GetEvent gets detailed information about a specific event by its database ID
-func (c *Client) GetEvent(requestID uint64) (*Event, error) {
+// Get gets detailed information about a specific event by its database ID
+func (s *EventsService) Get(requestID uint64) (*Event, error) {
 	path := fmt.Sprintf("/api/requests/%d", requestID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +81,7 @@ func (c *Client) GetEvent(requestID uint64) (*Event, error) {
 	var result struct {
 		Request Event `json:"request"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -92,15 +95,15 @@ type ReplayEventRequest struct {
 	ConnectionID  *uint64 `json:"connection_id,omitempty"`
 }
 
-// ReplayEvent replays a failed event by its event_id
-func (c *Client) ReplayEvent(req ReplayEventRequest) (*ReplayEventResponse, error) {
-	resp, err := c.doRequest("POST", "/api/replay-event", req, nil)
+// Replay replays a failed event by its event_id
+func (s *EventsService) Replay(req ReplayEventRequest) (*ReplayEventResponse, error) {
+	resp, err := s.client.doRequest("POST", "/api/replay-event", req, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var result ReplayEventResponse
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
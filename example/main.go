@@ -20,7 +20,7 @@ func main() {
 
 	// Example 1: List organizations
 	fmt.Println("=== Listing Organizations ===")
-	orgs, err := client.ListOrganizations()
+	orgs, err := client.Organizations.List()
 	if err != nil {
 		log.Fatalf("Failed to list organizations: %v", err)
 	}
@@ -41,7 +41,7 @@ func main() {
 
 	// Example 3: List projects
 	fmt.Println("\n=== Listing Projects ===")
-	projects, err := client.ListProjects()
+	projects, err := client.Projects.List()
 	if err != nil {
 		log.Fatalf("Failed to list projects: %v", err)
 	}
@@ -62,7 +62,7 @@ func main() {
 	// Example 4: List sources for first project
 	projectID := projects[0].ID
 	fmt.Printf("\n=== Listing Sources for Project: %s (ID: %d) ===\n", projects[0].Name, projectID)
-	sources, err := client.ListSources(projectID)
+	sources, err := client.Sources.List(projectID)
 	if err != nil {
 		log.Fatalf("Failed to list sources: %v", err)
 	}
@@ -75,7 +75,7 @@ func main() {
 	// Example 5: List events (if any)
 	fmt.Printf("\n=== Listing Recent Events for Project: %s ===\n", projects[0].Name)
 	limit := 10
-	events, err := client.ListEvents(projectID, &volley.ListEventsOptions{
+	events, err := client.Events.List(projectID, &volley.ListEventsOptions{
 		Limit: &limit,
 	})
 	if err != nil {
@@ -0,0 +1,182 @@
+package volley
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var placeholderPattern = regexp.MustCompile(`^\$\{[A-Z0-9_]+\}$`)
+var placeholderUnsafe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// SecretPlaceholder returns the placeholder ExportProject emits in place of a
+// secret value. Kind is "source" or "destination", so a source and a
+// destination with the same name do not share a variable, e.g.
+// ${VOLLEY_SOURCE_STRIPE_WEBHOOK_SECRET}
+func SecretPlaceholder(kind, name, field string) string {
+	name = placeholderUnsafe.ReplaceAllString(strings.ToUpper(kind+"_"+name+"_"+field), "_")
+	return "${VOLLEY_" + strings.Trim(name, "_") + "}"
+}
+
+// IsSecretPlaceholder reports whether value is a placeholder emitted by ExportProject
+func IsSecretPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(value)
+}
+
+// resolveSecret returns a spec's secret value, reading placeholders from the
+// environment
+func resolveSecret(value string) string {
+	if !IsSecretPlaceholder(value) {
+		return value
+	}
+	return os.Getenv(strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}"))
+}
+
+// ExportProject reads the sources, destinations and connections of a project
+// and returns them as a TopologySpec. Server-generated fields (IDs, timestamps,
+// ingestion IDs, counts) are omitted and secrets are replaced by placeholders.
+// Objects are sorted by name so the output is stable between exports.
+func (c *Client) ExportProject(projectID uint64) (*TopologySpec, error) {
+	projects, err := c.Projects.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	spec := &TopologySpec{}
+	for _, p := range projects {
+		if p.ID == projectID {
+			spec.Project = p.Name
+			break
+		}
+	}
+
+	live, err := c.fetchLiveTopology(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.fill(live)
+	return spec, nil
+}
+
+// fill populates the spec from live state
+func (s *TopologySpec) fill(live *liveTopology) {
+	for _, source := range live.sources {
+		src := SourceSpec{
+			Slug:         source.Slug,
+			EPS:          source.EPS,
+			AuthType:     source.AuthType,
+			Status:       source.Status,
+			AuthUsername: source.AuthUsername,
+			AuthKeyName:  source.AuthKeyName,
+		}
+		// verify_signature is explicit whenever there is a secret, so applying
+		// the export turns verification off again if it was disabled
+		if source.VerifySignature || source.WebhookSecretSet {
+			verify := source.VerifySignature
+			src.VerifySignature = &verify
+		}
+		if source.WebhookSecretSet {
+			src.WebhookSecret = SecretPlaceholder("source", source.Slug, "webhook_secret")
+		}
+		switch source.AuthType {
+		case "basic":
+			src.AuthPassword = SecretPlaceholder("source", source.Slug, "auth_password")
+		case "api_key":
+			src.AuthKey = SecretPlaceholder("source", source.Slug, "auth_key")
+		}
+		s.Sources = append(s.Sources, src)
+	}
+
+	for _, d := range live.destinations {
+		dest := DestinationSpec{
+			Name:           d.Name,
+			URL:            d.URL,
+			EPS:            d.EPS,
+			Status:         d.Status,
+			Method:         d.Method,
+			Headers:        d.Headers,
+			TimeoutSeconds: d.TimeoutSeconds,
+			AuthType:       d.AuthType,
+			AuthUsername:   d.AuthUsername,
+		}
+		if d.AuthSecretSet {
+			switch d.AuthType {
+			case "basic":
+				dest.AuthPassword = SecretPlaceholder("destination", d.Name, "auth_password")
+			case "bearer":
+				dest.AuthToken = SecretPlaceholder("destination", d.Name, "auth_token")
+			}
+		}
+		if d.ClientCertSet {
+			dest.ClientCert = SecretPlaceholder("destination", d.Name, "client_cert")
+			dest.ClientKey = SecretPlaceholder("destination", d.Name, "client_key")
+		}
+		s.Destinations = append(s.Destinations, dest)
+	}
+
+	for key, conn := range live.connections {
+		parts := strings.SplitN(key, " -> ", 2)
+		s.Connections = append(s.Connections, ConnectionSpec{
+			Source:      parts[0],
+			Destination: parts[1],
+			Status:      conn.Status,
+			EPS:         conn.EPS,
+			MaxRetries:  conn.MaxRetries,
+			Filters:     conn.Filters,
+			Transform:   conn.Transform,
+			Retry:       conn.Retry,
+		})
+	}
+
+	s.sort()
+}
+
+// sort orders objects by name so encodings are stable
+func (s *TopologySpec) sort() {
+	sort.Slice(s.Sources, func(i, j int) bool { return s.Sources[i].Slug < s.Sources[j].Slug })
+	sort.Slice(s.Destinations, func(i, j int) bool { return s.Destinations[i].Name < s.Destinations[j].Name })
+	sort.Slice(s.Connections, func(i, j int) bool { return s.Connections[i].Key() < s.Connections[j].Key() })
+}
+
+// EncodeTopology encodes a TopologySpec as "yaml" or "json"
+func EncodeTopology(spec *TopologySpec, format string) ([]byte, error) {
+	// Empty lists are encoded as [] rather than null so documents diff cleanly
+	out := *spec
+	if out.Sources == nil {
+		out.Sources = []SourceSpec{}
+	}
+	if out.Destinations == nil {
+		out.Destinations = []DestinationSpec{}
+	}
+	if out.Connections == nil {
+		out.Connections = []ConnectionSpec{}
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode topology: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "yaml", "yml", "":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(out); err != nil {
+			return nil, fmt.Errorf("failed to encode topology: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode topology: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported topology format %q", format)
+	}
+}
@@ -0,0 +1,112 @@
+package volley_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+)
+
+func TestExportProject(t *testing.T) {
+	backend := newProjectBackend()
+	backend.projects = []volley.Project{{ID: 1, Name: "Production"}}
+	stripe := backend.addSource("stripe", 10)
+	stripe.WebhookSecretSet = true
+	stripe.VerifySignature = true
+	github := backend.addSource("github", 5)
+	github.AuthType = "basic"
+	github.AuthUsername = "hooks"
+	github.WebhookSecretSet = true
+	api := backend.addDestination("api", "https://api.example.com/hooks", 5)
+	backend.addConnection(stripe.ID, api.ID, 5, 3)
+	backend.addConnection(github.ID, api.ID, 5, 3)
+	// A connection whose destination has been deleted cannot be referenced by name
+	backend.addConnection(github.ID, 9999, 5, 3)
+
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	spec, err := client.ExportProject(1)
+	if err != nil {
+		t.Fatalf("ExportProject failed: %v", err)
+	}
+
+	data, err := volley.EncodeTopology(spec, "yaml")
+	if err != nil {
+		t.Fatalf("EncodeTopology failed: %v", err)
+	}
+
+	want := `project: Production
+sources:
+  - slug: github
+    eps: 5
+    auth_type: basic
+    status: active
+    verify_signature: false
+    auth_username: hooks
+    webhook_secret: ${VOLLEY_SOURCE_GITHUB_WEBHOOK_SECRET}
+    auth_password: ${VOLLEY_SOURCE_GITHUB_AUTH_PASSWORD}
+  - slug: stripe
+    eps: 10
+    auth_type: none
+    status: active
+    verify_signature: true
+    webhook_secret: ${VOLLEY_SOURCE_STRIPE_WEBHOOK_SECRET}
+destinations:
+  - name: api
+    url: https://api.example.com/hooks
+    eps: 5
+    status: active
+connections:
+  - source: github
+    destination: api
+    status: enabled
+    eps: 5
+    max_retries: 3
+  - source: stripe
+    destination: api
+    status: enabled
+    eps: 5
+    max_retries: 3
+`
+	if string(data) != want {
+		t.Errorf("Unexpected export:\n%s\nwant:\n%s", data, want)
+	}
+
+	for _, forbidden := range []string{"ing_", "created_at", "connection_count", "id:"} {
+		if strings.Contains(string(data), forbidden) {
+			t.Errorf("Export should not contain %q", forbidden)
+		}
+	}
+
+	// The export round-trips into a spec that plans no changes
+	loaded, err := volley.LoadTopology(data)
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+	plan, err := client.PlanTopology(1, loaded, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected empty plan for exported spec, got:\n%s", plan)
+	}
+}
+
+func TestSecretPlaceholder(t *testing.T) {
+	p := volley.SecretPlaceholder("source", "stripe-webhooks", "webhook_secret")
+	if p != "${VOLLEY_SOURCE_STRIPE_WEBHOOKS_WEBHOOK_SECRET}" {
+		t.Errorf("Unexpected placeholder %s", p)
+	}
+	if volley.SecretPlaceholder("destination", "billing", "auth_password") == volley.SecretPlaceholder("source", "billing", "auth_password") {
+		t.Error("Expected sources and destinations with the same name to use different placeholders")
+	}
+	if !volley.IsSecretPlaceholder(p) {
+		t.Error("Expected placeholder to be recognised")
+	}
+	if volley.IsSecretPlaceholder("whsec_live_value") {
+		t.Error("Expected literal secret not to be treated as a placeholder")
+	}
+}
@@ -0,0 +1,296 @@
+// Package exporter collects Volley project health from the API and exposes it
+// in the Prometheus text exposition format.
+//
+// An Exporter periodically counts events by status from Events.List, builds
+// delivery latency histograms and status code counters from
+// DeliveryAttempts.List and reports each connection's configuration from
+// Connections.List. It implements http.Handler, so it can be mounted on any mux:
+//
+//	exp := exporter.New(client, exporter.Options{ProjectIDs: []uint64{1}})
+//	go exp.Run(ctx)
+//	http.Handle("/metrics", exp)
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+const (
+	// DefaultInterval is the default interval between collections
+	DefaultInterval = 30 * time.Second
+	// DefaultWindow is the default lookback window for event counts and for
+	// finding new delivery attempts
+	DefaultWindow = 5 * time.Minute
+
+	pageSize = 100
+
+	// seenMargin keeps counted attempts for a while after they leave the
+	// window, since the API filters by StartTime at one-second precision
+	seenMargin = time.Minute
+)
+
+// DefaultLatencyBuckets are the default upper bounds, in seconds, of the
+// delivery latency histogram
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// eventStatuses are the event statuses counted per source
+var eventStatuses = []string{"processed", "pending", "failed", "dropped"}
+
+// Options configures an Exporter
+type Options struct {
+	// ProjectIDs are the projects to collect. If empty, every project in the
+	// client's organization is collected.
+	ProjectIDs []uint64
+	// Interval between collections in Run (default DefaultInterval)
+	Interval time.Duration
+	// Window is the lookback for event counts and new delivery attempts
+	// (default DefaultWindow). It should be longer than Interval.
+	Window time.Duration
+	// LatencyBuckets are the histogram upper bounds in seconds (default DefaultLatencyBuckets)
+	LatencyBuckets []float64
+	// OnError is called by Run when a collection fails
+	OnError func(error)
+	// ClientMetrics, when set, adds the SDK's own request metrics to the
+	// output. Pass the same MemoryMetrics to volley.WithMetrics.
+	ClientMetrics *volley.MemoryMetrics
+}
+
+// Exporter collects project health and serves it to Prometheus
+type Exporter struct {
+	client *volley.Client
+	opts   Options
+
+	mu             sync.Mutex
+	projects       map[uint64]*projectState
+	collections    uint64
+	failures       uint64
+	lastCollection time.Time
+	lastDuration   time.Duration
+}
+
+// projectState holds what has been collected for one project
+type projectState struct {
+	sources      map[uint64]string // slug by ID
+	destinations map[uint64]string // name by ID
+	connections  []volley.Connection
+	// events counts events in the window by source slug and status
+	events map[[2]string]int64
+	// seen holds the IDs and times of attempts already counted
+	seen map[uint64]time.Time
+	// attempts accumulates delivery attempts by connection ID
+	attempts map[uint64]*attemptStats
+}
+
+// attemptStats are cumulative delivery attempt counters for a connection
+type attemptStats struct {
+	// codes counts attempts by status and status code
+	codes map[[2]string]uint64
+	// buckets counts attempts with a duration at or under each latency bucket
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// New creates an Exporter. Call Run or Collect to gather metrics.
+func New(client *volley.Client, opts Options) *Exporter {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if len(opts.LatencyBuckets) == 0 {
+		opts.LatencyBuckets = DefaultLatencyBuckets
+	}
+	opts.LatencyBuckets = append([]float64(nil), opts.LatencyBuckets...)
+	sort.Float64s(opts.LatencyBuckets)
+
+	return &Exporter{
+		client:   client,
+		opts:     opts,
+		projects: make(map[uint64]*projectState),
+	}
+}
+
+// Run collects immediately and then every Interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Collect(); err != nil && e.opts.OnError != nil {
+			e.opts.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect gathers metrics for every project once
+func (e *Exporter) Collect() error {
+	start := time.Now()
+
+	projectIDs := e.opts.ProjectIDs
+	if len(projectIDs) == 0 {
+		projects, err := e.client.Projects.List()
+		if err != nil {
+			e.record(start, err)
+			return fmt.Errorf("failed to list projects: %w", err)
+		}
+		for _, p := range projects {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+
+	var errs []error
+	for _, id := range projectIDs {
+		if err := e.collectProject(id, start); err != nil {
+			errs = append(errs, fmt.Errorf("project %d: %w", id, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+	e.record(start, err)
+	return err
+}
+
+func (e *Exporter) record(start time.Time, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.collections++
+	if err != nil {
+		e.failures++
+	}
+	e.lastCollection = start
+	e.lastDuration = time.Since(start)
+}
+
+func (e *Exporter) collectProject(projectID uint64, now time.Time) error {
+	sources, err := e.client.Sources.List(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to list sources: %w", err)
+	}
+	destinations, err := e.client.Destinations.List(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to list destinations: %w", err)
+	}
+	connections, err := e.client.Connections.List(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	since := now.Add(-e.opts.Window)
+	events := make(map[[2]string]int64)
+	one := 1
+	for _, src := range sources {
+		sourceID := src.ID
+		for _, status := range eventStatuses {
+			resp, err := e.client.Events.List(projectID, &volley.ListEventsOptions{
+				SourceID:  &sourceID,
+				Status:    status,
+				StartTime: &since,
+				Limit:     &one,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to count events: %w", err)
+			}
+			events[[2]string{src.Slug, status}] = resp.Total
+		}
+	}
+
+	attempts, err := e.fetchAttempts(projectID, since)
+	if err != nil {
+		return fmt.Errorf("failed to list delivery attempts: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.projects[projectID]
+	if !ok {
+		state = &projectState{seen: make(map[uint64]time.Time), attempts: make(map[uint64]*attemptStats)}
+		e.projects[projectID] = state
+	}
+	state.sources = make(map[uint64]string, len(sources))
+	for _, s := range sources {
+		state.sources[s.ID] = s.Slug
+	}
+	state.destinations = make(map[uint64]string, len(destinations))
+	for _, d := range destinations {
+		state.destinations[d.ID] = d.Name
+	}
+	state.connections = connections
+	state.events = events
+
+	for _, at := range attempts {
+		if _, dup := state.seen[at.ID]; dup {
+			continue
+		}
+		state.seen[at.ID] = at.CreatedAt
+		e.observe(state, at)
+	}
+	// Attempts older than the window will not be returned again
+	cutoff := since.Truncate(time.Second).Add(-seenMargin)
+	for id, created := range state.seen {
+		if created.Before(cutoff) {
+			delete(state.seen, id)
+		}
+	}
+	return nil
+}
+
+// observe adds an attempt to its connection's counters. The caller must hold e.mu.
+func (e *Exporter) observe(state *projectState, at volley.DeliveryAttempt) {
+	stats, ok := state.attempts[at.ConnectionID]
+	if !ok {
+		stats = &attemptStats{codes: make(map[[2]string]uint64), buckets: make([]uint64, len(e.opts.LatencyBuckets))}
+		state.attempts[at.ConnectionID] = stats
+	}
+
+	stats.codes[[2]string{at.Status, strconv.Itoa(at.StatusCode)}]++
+	seconds := float64(at.DurationMs) / 1000
+	for i, bound := range e.opts.LatencyBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.count++
+	stats.sum += seconds
+}
+
+func (e *Exporter) fetchAttempts(projectID uint64, since time.Time) ([]volley.DeliveryAttempt, error) {
+	var attempts []volley.DeliveryAttempt
+	limit := pageSize
+	offset := 0
+
+	for {
+		page := offset
+		resp, err := e.client.DeliveryAttempts.List(projectID, &volley.ListDeliveryAttemptsOptions{
+			StartTime: &since,
+			Sort:      "time_oldest",
+			Limit:     &limit,
+			Offset:    &page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, resp.Attempts...)
+		offset += len(resp.Attempts)
+		if len(resp.Attempts) < limit || int64(offset) >= resp.Total {
+			return attempts, nil
+		}
+	}
+}
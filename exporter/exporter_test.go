@@ -0,0 +1,194 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+	"github.com/volleyhq/volley-go/exporter"
+)
+
+func scrape(t *testing.T, exp *exporter.Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the text exposition content type, got %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func expectLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestExporterCollect(t *testing.T) {
+	status := http.StatusOK
+	dest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer dest.Close()
+
+	emu := emulator.New(&emulator.Options{RetryBackoff: time.Millisecond})
+	server := httptest.NewServer(emu)
+	defer func() {
+		server.Close()
+		emu.Close()
+	}()
+	clientMetrics := volley.NewMemoryMetrics()
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithMetrics(clientMetrics))
+
+	src, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+	d, err := client.CreateDestination(1, volley.CreateDestinationRequest{Name: "api", URL: dest.URL})
+	if err != nil {
+		t.Fatalf("CreateDestination failed: %v", err)
+	}
+	if _, err := client.CreateConnection(1, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: d.ID}); err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	send := func() {
+		t.Helper()
+		if _, err := client.SendWebhook(src.IngestionID, map[string]int{"n": 1}); err != nil {
+			t.Fatalf("SendWebhook failed: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := emu.WaitIdle(ctx); err != nil {
+			t.Fatalf("deliveries did not finish: %v", err)
+		}
+	}
+	send()
+	send()
+
+	exp := exporter.New(client, exporter.Options{ProjectIDs: []uint64{1}, ClientMetrics: clientMetrics})
+	if err := exp.Collect(); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	body := scrape(t, exp)
+	expectLines(t, body,
+		"# TYPE volley_events gauge",
+		`volley_events{project="1",source="stripe",status="processed"} 2`,
+		`volley_events{project="1",source="stripe",status="failed"} 0`,
+		"# TYPE volley_delivery_attempts_total counter",
+		`volley_delivery_attempts_total{project="1",source="stripe",destination="api",connection="1",status="success",code="200"} 2`,
+		"# TYPE volley_delivery_duration_seconds histogram",
+		`volley_delivery_duration_seconds_bucket{project="1",source="stripe",destination="api",connection="1",le="+Inf"} 2`,
+		`volley_delivery_duration_seconds_count{project="1",source="stripe",destination="api",connection="1"} 2`,
+		`volley_connection_enabled{project="1",source="stripe",destination="api",connection="1"} 1`,
+		"volley_exporter_collections_total 1",
+		"volley_exporter_collection_errors_total 0",
+		`volley_client_requests_total{operation="POST /api/projects/:id/sources",code="201"} 1`,
+		`volley_client_requests_total{operation="POST /hook/:ingestion_id",code="202"} 2`,
+		`volley_client_request_duration_seconds_count{operation="GET /api/projects/:id/connections"} 1`,
+	)
+
+	// Attempts already counted are not counted again
+	status = http.StatusInternalServerError
+	send()
+	if err := exp.Collect(); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	body = scrape(t, exp)
+	expectLines(t, body,
+		`volley_events{project="1",source="stripe",status="failed"} 1`,
+		`volley_delivery_attempts_total{project="1",source="stripe",destination="api",connection="1",status="success",code="200"} 2`,
+		`volley_delivery_attempts_total{project="1",source="stripe",destination="api",connection="1",status="failed",code="500"} 1`,
+		`volley_delivery_duration_seconds_count{project="1",source="stripe",destination="api",connection="1"} 3`,
+		"volley_exporter_collections_total 2",
+	)
+}
+
+func TestExporterBoundaryAttempts(t *testing.T) {
+	// The API filters by whole seconds, so an attempt just before the start
+	// of the window is returned by every collection
+	window := 2 * time.Second
+	created := time.Now().Add(-window).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/projects/1/delivery-attempts":
+			body = volley.ListDeliveryAttemptsResponse{
+				PaginatedResponse: volley.PaginatedResponse{Total: 1},
+				Attempts:          []volley.DeliveryAttempt{{ID: 7, ConnectionID: 1, Status: "success", StatusCode: 200, CreatedAt: created}},
+			}
+		default:
+			body = map[string][]interface{}{"sources": {}, "destinations": {}, "connections": {}}
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	exp := exporter.New(volley.NewClient("test-token", volley.WithBaseURL(server.URL)), exporter.Options{ProjectIDs: []uint64{1}, Window: window})
+	for i := 0; i < 2; i++ {
+		if err := exp.Collect(); err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+	}
+	expectLines(t, scrape(t, exp),
+		`volley_delivery_attempts_total{project="1",source="",destination="",connection="1",status="success",code="200"} 1`,
+	)
+}
+
+func TestExporterCollectError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": "boom"}`))
+	}))
+	defer server.Close()
+
+	exp := exporter.New(volley.NewClient("test-token", volley.WithBaseURL(server.URL)), exporter.Options{ProjectIDs: []uint64{1}})
+	if err := exp.Collect(); err == nil {
+		t.Fatal("Expected Collect to fail")
+	}
+	expectLines(t, scrape(t, exp),
+		"volley_exporter_collections_total 1",
+		"volley_exporter_collection_errors_total 1",
+	)
+}
+
+func TestExporterEscapesLabels(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer func() {
+		server.Close()
+		emu.Close()
+	}()
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	src, _ := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	d, err := client.CreateDestination(1, volley.CreateDestinationRequest{Name: "say \"hi\"\\", URL: "https://example.com/hooks"})
+	if err != nil {
+		t.Fatalf("CreateDestination failed: %v", err)
+	}
+	if _, err := client.CreateConnection(1, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: d.ID}); err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	exp := exporter.New(client, exporter.Options{ProjectIDs: []uint64{1}})
+	if err := exp.Collect(); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	expectLines(t, scrape(t, exp),
+		`volley_connection_enabled{project="1",source="stripe",destination="say \"hi\"\\",connection="1"} 1`,
+	)
+}
@@ -0,0 +1,273 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// contentType is the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ServeHTTP writes the metrics from the most recent collection
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var buf bytes.Buffer
+	e.write(&buf)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(buf.Bytes())
+}
+
+// write renders every metric family. Samples are sorted so output is stable.
+func (e *Exporter) write(buf *bytes.Buffer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	m := &metricWriter{buf: buf}
+	projectIDs := sortedKeys(e.projects)
+
+	m.family("volley_events", "gauge", fmt.Sprintf("Events received in the last %s by source and status.", e.opts.Window))
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		keys := make([][2]string, 0, len(state.events))
+		for key := range state.events {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i][0] != keys[j][0] {
+				return keys[i][0] < keys[j][0]
+			}
+			return keys[i][1] < keys[j][1]
+		})
+		for _, key := range keys {
+			m.sample("volley_events", labels{{"project", id(pid)}, {"source", key[0]}, {"status", key[1]}}, float64(state.events[key]))
+		}
+	}
+
+	m.family("volley_delivery_attempts_total", "counter", "Delivery attempts by connection, status and status code.")
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		for _, connID := range sortedKeys(state.attempts) {
+			stats := state.attempts[connID]
+			codes := make([][2]string, 0, len(stats.codes))
+			for key := range stats.codes {
+				codes = append(codes, key)
+			}
+			sort.Slice(codes, func(i, j int) bool {
+				if codes[i][0] != codes[j][0] {
+					return codes[i][0] < codes[j][0]
+				}
+				return codes[i][1] < codes[j][1]
+			})
+			for _, key := range codes {
+				l := append(state.connectionLabels(pid, connID), label{"status", key[0]}, label{"code", key[1]})
+				m.sample("volley_delivery_attempts_total", l, float64(stats.codes[key]))
+			}
+		}
+	}
+
+	m.family("volley_delivery_duration_seconds", "histogram", "Delivery attempt latency by connection.")
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		for _, connID := range sortedKeys(state.attempts) {
+			stats := state.attempts[connID]
+			base := state.connectionLabels(pid, connID)
+			for i, bound := range e.opts.LatencyBuckets {
+				m.sample("volley_delivery_duration_seconds_bucket", append(base, label{"le", formatFloat(bound)}), float64(stats.buckets[i]))
+			}
+			m.sample("volley_delivery_duration_seconds_bucket", append(base, label{"le", "+Inf"}), float64(stats.count))
+			m.sample("volley_delivery_duration_seconds_sum", base, stats.sum)
+			m.sample("volley_delivery_duration_seconds_count", base, float64(stats.count))
+		}
+	}
+
+	m.family("volley_connection_enabled", "gauge", "Whether a connection is enabled (1) or disabled (0).")
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		for _, conn := range state.sortedConnections() {
+			enabled := 0.0
+			if conn.Status == "enabled" {
+				enabled = 1
+			}
+			m.sample("volley_connection_enabled", state.connectionLabels(pid, conn.ID), enabled)
+		}
+	}
+
+	m.family("volley_connection_eps", "gauge", "Configured events per second limit of a connection.")
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		for _, conn := range state.sortedConnections() {
+			m.sample("volley_connection_eps", state.connectionLabels(pid, conn.ID), float64(conn.EPS))
+		}
+	}
+
+	m.family("volley_connection_max_retries", "gauge", "Configured maximum delivery retries of a connection.")
+	for _, pid := range projectIDs {
+		state := e.projects[pid]
+		for _, conn := range state.sortedConnections() {
+			m.sample("volley_connection_max_retries", state.connectionLabels(pid, conn.ID), float64(conn.MaxRetries))
+		}
+	}
+
+	if e.opts.ClientMetrics != nil {
+		writeClientMetrics(m, e.opts.ClientMetrics.Snapshot())
+	}
+
+	m.family("volley_exporter_collections_total", "counter", "Collections run by the exporter.")
+	m.sample("volley_exporter_collections_total", nil, float64(e.collections))
+	m.family("volley_exporter_collection_errors_total", "counter", "Collections that failed for at least one project.")
+	m.sample("volley_exporter_collection_errors_total", nil, float64(e.failures))
+	if !e.lastCollection.IsZero() {
+		m.family("volley_exporter_last_collection_timestamp_seconds", "gauge", "Unix time the last collection started.")
+		m.sample("volley_exporter_last_collection_timestamp_seconds", nil, float64(e.lastCollection.UnixNano())/1e9)
+		m.family("volley_exporter_collection_duration_seconds", "gauge", "Duration of the last collection.")
+		m.sample("volley_exporter_collection_duration_seconds", nil, e.lastDuration.Seconds())
+	}
+}
+
+// writeClientMetrics renders the SDK's request metrics by operation
+func writeClientMetrics(m *metricWriter, snapshot []volley.OperationStats) {
+	m.family("volley_client_requests_total", "counter", "API requests made by the client by operation and status code.")
+	for _, op := range snapshot {
+		codes := make([]int, 0, len(op.StatusCodes))
+		for code := range op.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			m.sample("volley_client_requests_total", labels{{"operation", op.Operation}, {"code", strconv.Itoa(code)}}, float64(op.StatusCodes[code]))
+		}
+	}
+
+	counters := []struct {
+		name, help string
+		value      func(volley.OperationStats) uint64
+	}{
+		{"volley_client_request_errors_total", "API requests that failed without a response.", func(op volley.OperationStats) uint64 { return op.Errors }},
+		{"volley_client_retries_total", "API request retries.", func(op volley.OperationStats) uint64 { return op.Retries }},
+		{"volley_client_request_bytes_total", "Bytes sent in API request bodies.", func(op volley.OperationStats) uint64 { return op.RequestBytes }},
+		{"volley_client_response_bytes_total", "Bytes received in API response bodies.", func(op volley.OperationStats) uint64 { return op.ResponseBytes }},
+	}
+	for _, c := range counters {
+		m.family(c.name, "counter", c.help)
+		for _, op := range snapshot {
+			m.sample(c.name, labels{{"operation", op.Operation}}, float64(c.value(op)))
+		}
+	}
+
+	m.family("volley_client_request_duration_seconds", "histogram", "API request latency by operation.")
+	for _, op := range snapshot {
+		base := labels{{"operation", op.Operation}}
+		for _, b := range op.DurationBuckets {
+			m.sample("volley_client_request_duration_seconds_bucket", append(base, label{"le", formatFloat(b.UpperBound.Seconds())}), float64(b.Count))
+		}
+		m.sample("volley_client_request_duration_seconds_bucket", append(base, label{"le", "+Inf"}), float64(op.Requests))
+		m.sample("volley_client_request_duration_seconds_sum", base, op.DurationSum.Seconds())
+		m.sample("volley_client_request_duration_seconds_count", base, float64(op.Requests))
+	}
+}
+
+// connectionLabels labels a connection by project, source, destination and ID.
+// Sources and destinations of deleted connections are left empty.
+func (state *projectState) connectionLabels(projectID, connectionID uint64) labels {
+	var source, destination string
+	for _, conn := range state.connections {
+		if conn.ID == connectionID {
+			source = state.sources[conn.SourceID]
+			destination = state.destinations[conn.DestinationID]
+			break
+		}
+	}
+	return labels{
+		{"project", id(projectID)},
+		{"source", source},
+		{"destination", destination},
+		{"connection", id(connectionID)},
+	}
+}
+
+func (state *projectState) sortedConnections() []volley.Connection {
+	conns := append([]volley.Connection(nil), state.connections...)
+	sort.Slice(conns, func(i, j int) bool { return conns[i].ID < conns[j].ID })
+	return conns
+}
+
+type label struct {
+	name, value string
+}
+
+type labels []label
+
+// metricWriter writes metric families in the text exposition format
+type metricWriter struct {
+	buf *bytes.Buffer
+}
+
+func (m *metricWriter) family(name, typ, help string) {
+	fmt.Fprintf(m.buf, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+func (m *metricWriter) sample(name string, l labels, value float64) {
+	m.buf.WriteString(name)
+	if len(l) > 0 {
+		m.buf.WriteByte('{')
+		for i, lb := range l {
+			if i > 0 {
+				m.buf.WriteByte(',')
+			}
+			m.buf.WriteString(lb.name)
+			m.buf.WriteString(`="`)
+			m.buf.WriteString(escapeLabel(lb.value))
+			m.buf.WriteByte('"')
+		}
+		m.buf.WriteByte('}')
+	}
+	m.buf.WriteByte(' ')
+	m.buf.WriteString(formatFloat(value))
+	m.buf.WriteByte('\n')
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func id(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+func sortedKeys[T any](m map[uint64]T) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
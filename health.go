@@ -0,0 +1,408 @@
+package volley
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHealthInterval is the default interval between health samples
+	DefaultHealthInterval = 30 * time.Second
+	// DefaultHealthWindow is the default rolling window used for health statistics
+	DefaultHealthWindow = 5 * time.Minute
+	// DefaultFailureThreshold is the default failure rate above which a destination is unhealthy
+	DefaultFailureThreshold = 0.2
+	// DefaultHealthMinAttempts is the default number of attempts required before a state change
+	DefaultHealthMinAttempts = 10
+
+	healthPageSize = 100
+)
+
+// HealthMonitorOptions configures a HealthMonitor
+type HealthMonitorOptions struct {
+	// ProjectID is the project whose destinations are monitored
+	ProjectID uint64
+	// DestinationIDs limits monitoring to specific destinations.
+	// If empty, all destinations in the project are monitored.
+	DestinationIDs []uint64
+
+	// Interval between samples (default DefaultHealthInterval)
+	Interval time.Duration
+	// Window is the rolling window statistics are computed over (default DefaultHealthWindow)
+	Window time.Duration
+	// MinAttempts is the number of attempts in the window required before
+	// the state of a destination may change (default DefaultHealthMinAttempts)
+	MinAttempts int
+
+	// FailureThreshold marks a destination unhealthy when its failure rate
+	// exceeds it (default DefaultFailureThreshold)
+	FailureThreshold float64
+	// RecoveryThreshold marks an unhealthy destination healthy again once its
+	// failure rate drops to or below it (default FailureThreshold / 2)
+	RecoveryThreshold float64
+	// LatencyThreshold marks a destination unhealthy when its average latency
+	// exceeds it. Zero disables latency checks.
+	LatencyThreshold time.Duration
+	// RecoveryLatency is the average latency an unhealthy destination must get
+	// back under to recover (default 80% of LatencyThreshold)
+	RecoveryLatency time.Duration
+
+	// OnUnhealthy is called when a destination crosses a threshold
+	OnUnhealthy func(HealthEvent)
+	// OnRecovered is called when an unhealthy destination recovers
+	OnRecovered func(HealthEvent)
+	// OnError is called by Run when a sample fails
+	OnError func(error)
+
+	// AutoDisable disables the enabled connections of a destination that is
+	// hard down. Disabled connections receive no deliveries, so the destination
+	// never reaches MinAttempts again and OnRecovered does not fire for it;
+	// re-enable the connections listed in HealthEvent.DisabledConnections
+	// once the destination is fixed.
+	AutoDisable bool
+	// HardDownThreshold is the failure rate at or above which a destination
+	// is considered hard down (default 1.0, i.e. every attempt failed)
+	HardDownThreshold float64
+}
+
+// HealthStatus is a snapshot of a destination's health over the rolling window
+type HealthStatus struct {
+	DestinationID uint64        `json:"destination_id"`
+	Healthy       bool          `json:"healthy"`
+	HardDown      bool          `json:"hard_down"`
+	Attempts      int           `json:"attempts"`
+	Failures      int           `json:"failures"`
+	FailureRate   float64       `json:"failure_rate"`
+	AvgLatency    time.Duration `json:"avg_latency"`
+	P95Latency    time.Duration `json:"p95_latency"`
+	LastSampled   time.Time     `json:"last_sampled"`
+}
+
+// HealthEvent is passed to the HealthMonitor callbacks on a state change
+type HealthEvent struct {
+	Status HealthStatus
+	// Reason describes which threshold was crossed
+	Reason string
+	// DisabledConnections lists connections disabled by AutoDisable, if any
+	DisabledConnections []uint64
+	At                  time.Time
+}
+
+// HealthMonitor periodically samples delivery attempts per destination and
+// fires callbacks when failure rate or latency thresholds are crossed
+type HealthMonitor struct {
+	client *Client
+	opts   HealthMonitorOptions
+
+	mu     sync.Mutex
+	states map[uint64]*destinationHealth
+}
+
+type destinationHealth struct {
+	attempts  map[uint64]DeliveryAttempt
+	unhealthy bool
+	hardDown  bool
+	lastSeen  time.Time
+	status    HealthStatus
+}
+
+// NewHealthMonitor creates a new HealthMonitor for a project
+func NewHealthMonitor(client *Client, opts HealthMonitorOptions) *HealthMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultHealthInterval
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultHealthWindow
+	}
+	if opts.MinAttempts <= 0 {
+		opts.MinAttempts = DefaultHealthMinAttempts
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultFailureThreshold
+	}
+	if opts.RecoveryThreshold <= 0 || opts.RecoveryThreshold > opts.FailureThreshold {
+		opts.RecoveryThreshold = opts.FailureThreshold / 2
+	}
+	if opts.LatencyThreshold > 0 && (opts.RecoveryLatency <= 0 || opts.RecoveryLatency > opts.LatencyThreshold) {
+		opts.RecoveryLatency = opts.LatencyThreshold * 8 / 10
+	}
+	if opts.HardDownThreshold <= 0 || opts.HardDownThreshold > 1 {
+		opts.HardDownThreshold = 1
+	}
+
+	return &HealthMonitor{
+		client: client,
+		opts:   opts,
+		states: make(map[uint64]*destinationHealth),
+	}
+}
+
+// Run samples immediately and then every Interval until ctx is cancelled.
+// Sampling errors are reported to OnError and do not stop the monitor.
+func (m *HealthMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(); err != nil && m.opts.OnError != nil {
+			m.opts.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll takes a single sample of every monitored destination and fires any
+// callbacks for state changes
+func (m *HealthMonitor) Poll() error {
+	destinationIDs := m.opts.DestinationIDs
+	if len(destinationIDs) == 0 {
+		destinations, err := m.client.Destinations.List(m.opts.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to list destinations: %w", err)
+		}
+		for _, d := range destinations {
+			destinationIDs = append(destinationIDs, d.ID)
+		}
+	}
+
+	var errs []error
+	for _, id := range destinationIDs {
+		if err := m.sample(id); err != nil {
+			errs = append(errs, fmt.Errorf("destination %d: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Status returns the latest health snapshot for a destination
+func (m *HealthMonitor) Status(destinationID uint64) (HealthStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[destinationID]
+	if !ok {
+		return HealthStatus{}, false
+	}
+	return state.status, true
+}
+
+// Statuses returns the latest health snapshot for every monitored destination
+func (m *HealthMonitor) Statuses() []HealthStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]HealthStatus, 0, len(m.states))
+	for _, state := range m.states {
+		statuses = append(statuses, state.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].DestinationID < statuses[j].DestinationID
+	})
+	return statuses
+}
+
+// sample fetches new attempts for a destination and re-evaluates its state
+func (m *HealthMonitor) sample(destinationID uint64) error {
+	now := time.Now()
+	windowStart := now.Add(-m.opts.Window)
+
+	m.mu.Lock()
+	state, ok := m.states[destinationID]
+	if !ok {
+		state = &destinationHealth{attempts: make(map[uint64]DeliveryAttempt)}
+		state.status = HealthStatus{DestinationID: destinationID, Healthy: true}
+		m.states[destinationID] = state
+	}
+	since := windowStart
+	if state.lastSeen.After(since) {
+		// Overlap slightly so attempts recorded late are not missed;
+		// duplicates are dropped by ID below.
+		since = state.lastSeen.Add(-m.opts.Interval)
+	}
+	m.mu.Unlock()
+
+	attempts, err := m.fetchAttempts(destinationID, since)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	for _, a := range attempts {
+		state.attempts[a.ID] = a
+		if a.CreatedAt.After(state.lastSeen) {
+			state.lastSeen = a.CreatedAt
+		}
+	}
+	for id, a := range state.attempts {
+		if a.CreatedAt.Before(windowStart) {
+			delete(state.attempts, id)
+		}
+	}
+
+	status := m.computeStatus(destinationID, state, now)
+	event, recovered := m.transition(state, &status)
+	state.status = status
+	m.mu.Unlock()
+
+	if event == nil {
+		return nil
+	}
+
+	if recovered {
+		if m.opts.OnRecovered != nil {
+			m.opts.OnRecovered(*event)
+		}
+		return nil
+	}
+
+	var disableErr error
+	if m.opts.AutoDisable && status.HardDown {
+		event.DisabledConnections, disableErr = m.disableConnections(destinationID)
+	}
+	if m.opts.OnUnhealthy != nil {
+		m.opts.OnUnhealthy(*event)
+	}
+	return disableErr
+}
+
+// fetchAttempts pages through delivery attempts for a destination since a point in time
+func (m *HealthMonitor) fetchAttempts(destinationID uint64, since time.Time) ([]DeliveryAttempt, error) {
+	var attempts []DeliveryAttempt
+	limit := healthPageSize
+	offset := 0
+
+	for {
+		page := offset
+		resp, err := m.client.DeliveryAttempts.List(m.opts.ProjectID, &ListDeliveryAttemptsOptions{
+			DestinationID: &destinationID,
+			StartTime:     &since,
+			Sort:          "time_oldest",
+			Limit:         &limit,
+			Offset:        &page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, resp.Attempts...)
+		offset += len(resp.Attempts)
+		if len(resp.Attempts) < limit || int64(offset) >= resp.Total {
+			return attempts, nil
+		}
+	}
+}
+
+// computeStatus derives window statistics from the attempts held in state
+func (m *HealthMonitor) computeStatus(destinationID uint64, state *destinationHealth, now time.Time) HealthStatus {
+	status := HealthStatus{
+		DestinationID: destinationID,
+		Healthy:       !state.unhealthy,
+		HardDown:      state.hardDown,
+		LastSampled:   now,
+	}
+
+	latencies := make([]int64, 0, len(state.attempts))
+	var total int64
+	for _, a := range state.attempts {
+		status.Attempts++
+		if a.Status == "failed" {
+			status.Failures++
+		}
+		latencies = append(latencies, a.DurationMs)
+		total += a.DurationMs
+	}
+
+	if status.Attempts > 0 {
+		status.FailureRate = float64(status.Failures) / float64(status.Attempts)
+		status.AvgLatency = time.Duration(total/int64(status.Attempts)) * time.Millisecond
+
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		idx := (len(latencies)*95+99)/100 - 1
+		status.P95Latency = time.Duration(latencies[idx]) * time.Millisecond
+	}
+
+	return status
+}
+
+// transition applies the hysteresis rules and returns an event on state change.
+// The second return value reports whether the change is a recovery.
+func (m *HealthMonitor) transition(state *destinationHealth, status *HealthStatus) (*HealthEvent, bool) {
+	if status.Attempts < m.opts.MinAttempts {
+		return nil, false
+	}
+
+	latencyHigh := m.opts.LatencyThreshold > 0 && status.AvgLatency > m.opts.LatencyThreshold
+	latencyOK := m.opts.LatencyThreshold == 0 || status.AvgLatency <= m.opts.RecoveryLatency
+
+	if !state.unhealthy {
+		var reason string
+		switch {
+		case status.FailureRate > m.opts.FailureThreshold:
+			reason = fmt.Sprintf("failure rate %.1f%% exceeds %.1f%% over %s",
+				status.FailureRate*100, m.opts.FailureThreshold*100, m.opts.Window)
+		case latencyHigh:
+			reason = fmt.Sprintf("average latency %s exceeds %s over %s",
+				status.AvgLatency, m.opts.LatencyThreshold, m.opts.Window)
+		default:
+			return nil, false
+		}
+
+		state.unhealthy = true
+		state.hardDown = status.FailureRate >= m.opts.HardDownThreshold
+		status.Healthy = false
+		status.HardDown = state.hardDown
+		return &HealthEvent{Status: *status, Reason: reason, At: status.LastSampled}, false
+	}
+
+	if status.FailureRate <= m.opts.RecoveryThreshold && latencyOK {
+		state.unhealthy = false
+		state.hardDown = false
+		status.Healthy = true
+		status.HardDown = false
+		reason := fmt.Sprintf("failure rate %.1f%% at or below %.1f%%",
+			status.FailureRate*100, m.opts.RecoveryThreshold*100)
+		return &HealthEvent{Status: *status, Reason: reason, At: status.LastSampled}, true
+	}
+
+	if !state.hardDown && status.FailureRate >= m.opts.HardDownThreshold {
+		state.hardDown = true
+		status.HardDown = true
+		reason := fmt.Sprintf("hard down: failure rate %.1f%% over %s", status.FailureRate*100, m.opts.Window)
+		return &HealthEvent{Status: *status, Reason: reason, At: status.LastSampled}, false
+	}
+
+	return nil, false
+}
+
+// disableConnections disables every enabled connection delivering to a destination
+func (m *HealthMonitor) disableConnections(destinationID uint64) ([]uint64, error) {
+	connections, err := m.client.Connections.List(m.opts.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	var disabled []uint64
+	var errs []error
+	for _, conn := range connections {
+		if conn.DestinationID != destinationID || conn.Status == "disabled" {
+			continue
+		}
+		if _, err := m.client.Connections.Update(conn.ID, UpdateConnectionRequest{Status: "disabled"}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to disable connection %d: %w", conn.ID, err))
+			continue
+		}
+		disabled = append(disabled, conn.ID)
+	}
+
+	return disabled, errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package volley_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// attemptsServer serves a mutable set of delivery attempts and connections
+type attemptsServer struct {
+	mu       sync.Mutex
+	attempts []map[string]interface{}
+	nextID   int
+	disabled []string
+}
+
+func (s *attemptsServer) add(n int, status string, durationMs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.nextID++
+		s.attempts = append(s.attempts, map[string]interface{}{
+			"id":            s.nextID,
+			"event_id":      "evt_test",
+			"connection_id": 7,
+			"status":        status,
+			"status_code":   200,
+			"duration_ms":   durationMs,
+			"created_at":    time.Now().Format(time.RFC3339Nano),
+		})
+	}
+}
+
+func (s *attemptsServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/projects/1/delivery-attempts":
+			if r.URL.Query().Get("destination_id") != "5" {
+				t.Errorf("Expected destination_id '5', got %s", r.URL.Query().Get("destination_id"))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"attempts": s.attempts,
+				"total":    len(s.attempts),
+				"limit":    100,
+				"offset":   0,
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/projects/1/connections":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"connections": []map[string]interface{}{
+					{"id": 7, "source_id": 1, "destination_id": 5, "status": "enabled"},
+					{"id": 8, "source_id": 1, "destination_id": 6, "status": "enabled"},
+				},
+			})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/connections/7":
+			var req volley.UpdateConnectionRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			s.disabled = append(s.disabled, req.Status)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"connection": map[string]interface{}{"id": 7, "status": req.Status},
+			})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestHealthMonitorThresholdsAndRecovery(t *testing.T) {
+	backend := &attemptsServer{}
+	server := createTestServer(backend.handler(t))
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	var unhealthy, recovered []volley.HealthEvent
+	monitor := volley.NewHealthMonitor(client, volley.HealthMonitorOptions{
+		ProjectID:        1,
+		DestinationIDs:   []uint64{5},
+		MinAttempts:      5,
+		FailureThreshold: 0.2,
+		OnUnhealthy:      func(e volley.HealthEvent) { unhealthy = append(unhealthy, e) },
+		OnRecovered:      func(e volley.HealthEvent) { recovered = append(recovered, e) },
+	})
+
+	backend.add(7, "success", 100)
+	backend.add(3, "failed", 100)
+	if err := monitor.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(unhealthy) != 1 {
+		t.Fatalf("Expected 1 unhealthy event, got %d", len(unhealthy))
+	}
+	if unhealthy[0].Status.FailureRate != 0.3 {
+		t.Errorf("Expected failure rate 0.3, got %f", unhealthy[0].Status.FailureRate)
+	}
+
+	// 3/20 = 15% is below the failure threshold but above the recovery
+	// threshold, so the destination must stay unhealthy
+	backend.add(10, "success", 100)
+	monitor.Poll()
+	if len(recovered) != 0 {
+		t.Fatalf("Expected no recovery inside the hysteresis band, got %d", len(recovered))
+	}
+
+	backend.add(20, "success", 100)
+	monitor.Poll()
+	if len(recovered) != 1 {
+		t.Fatalf("Expected 1 recovery event, got %d", len(recovered))
+	}
+	if len(unhealthy) != 1 {
+		t.Errorf("Expected no additional unhealthy events, got %d", len(unhealthy))
+	}
+
+	status, ok := monitor.Status(5)
+	if !ok || !status.Healthy {
+		t.Errorf("Expected destination 5 to be healthy, got %+v", status)
+	}
+}
+
+func TestHealthMonitorAutoDisable(t *testing.T) {
+	backend := &attemptsServer{}
+	server := createTestServer(backend.handler(t))
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	var event volley.HealthEvent
+	monitor := volley.NewHealthMonitor(client, volley.HealthMonitorOptions{
+		ProjectID:      1,
+		DestinationIDs: []uint64{5},
+		MinAttempts:    3,
+		AutoDisable:    true,
+		OnUnhealthy:    func(e volley.HealthEvent) { event = e },
+	})
+
+	backend.add(4, "failed", 30000)
+	if err := monitor.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	if !event.Status.HardDown {
+		t.Error("Expected destination to be hard down")
+	}
+	if len(event.DisabledConnections) != 1 || event.DisabledConnections[0] != 7 {
+		t.Errorf("Expected connection 7 to be disabled, got %v", event.DisabledConnections)
+	}
+	if len(backend.disabled) != 1 || backend.disabled[0] != "disabled" {
+		t.Errorf("Expected one disable request, got %v", backend.disabled)
+	}
+}
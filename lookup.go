@@ -0,0 +1,203 @@
+package volley
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLookupCacheTTL is how long the services' Find methods reuse a listing
+const DefaultLookupCacheTTL = 30 * time.Second
+
+// ErrNotFound is returned by the services' Find methods when nothing matches
+var ErrNotFound = errors.New("not found")
+
+// WithLookupCacheTTL sets how long the services' Find methods cache listings.
+// A ttl of zero or less disables caching.
+func WithLookupCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.lookup = newLookupCache(ttl)
+	}
+}
+
+// InvalidateLookupCache drops all cached listings. Changes made through the
+// client invalidate the cache automatically; call this after changes made elsewhere.
+func (c *Client) InvalidateLookupCache() {
+	c.lookup.clear()
+}
+
+// FindBySlug returns the accessible organization with the given slug
+func (s *OrganizationsService) FindBySlug(slug string) (*Organization, error) {
+	return find(s.client, "orgs", s.List,
+		func(o Organization) bool { return o.Slug == slug },
+		fmt.Sprintf("organization %q", slug))
+}
+
+// FindByName returns the current organization's project with the given name
+func (s *ProjectsService) FindByName(name string) (*Project, error) {
+	return find(s.client, "projects:"+s.client.orgKey(), s.List,
+		func(p Project) bool { return p.Name == name },
+		fmt.Sprintf("project %q", name))
+}
+
+// Default returns the current organization's default project
+func (s *ProjectsService) Default() (*Project, error) {
+	return find(s.client, "projects:"+s.client.orgKey(), s.List,
+		func(p Project) bool { return p.IsDefault },
+		"default project")
+}
+
+// FindBySlug returns the project's source with the given slug
+func (s *SourcesService) FindBySlug(projectID uint64, slug string) (*Source, error) {
+	return find(s.client, fmt.Sprintf("sources:%d", projectID), func() ([]Source, error) { return s.List(projectID) },
+		func(src Source) bool { return src.Slug == slug },
+		fmt.Sprintf("source %q", slug))
+}
+
+// FindByIngestionID returns the project's source with the given ingestion ID
+func (s *SourcesService) FindByIngestionID(projectID uint64, ingestionID string) (*Source, error) {
+	return find(s.client, fmt.Sprintf("sources:%d", projectID), func() ([]Source, error) { return s.List(projectID) },
+		func(src Source) bool { return src.IngestionID == ingestionID },
+		fmt.Sprintf("source with ingestion ID %q", ingestionID))
+}
+
+// FindByName returns the project's destination with the given name
+func (s *DestinationsService) FindByName(projectID uint64, name string) (*Destination, error) {
+	return find(s.client, fmt.Sprintf("destinations:%d", projectID), func() ([]Destination, error) { return s.List(projectID) },
+		func(d Destination) bool { return d.Name == name },
+		fmt.Sprintf("destination %q", name))
+}
+
+// Find returns the project's connection from a source to a destination
+func (s *ConnectionsService) Find(projectID, sourceID, destinationID uint64) (*Connection, error) {
+	return find(s.client, fmt.Sprintf("connections:%d", projectID), func() ([]Connection, error) { return s.List(projectID) },
+		func(conn Connection) bool { return conn.SourceID == sourceID && conn.DestinationID == destinationID },
+		fmt.Sprintf("connection from source %d to destination %d", sourceID, destinationID))
+}
+
+func (c *Client) orgKey() string {
+	if c.organizationID == nil {
+		return "default"
+	}
+	return fmt.Sprintf("%d", *c.organizationID)
+}
+
+// find returns the first item matching match in a cached listing. A miss on
+// cached data refreshes the listing once before reporting ErrNotFound.
+func find[T any](c *Client, key string, list func() ([]T, error), match func(T) bool, what string) (*T, error) {
+	for {
+		items, cached, err := cachedList(c.lookup, key, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if match(items[i]) {
+				item := items[i]
+				return &item, nil
+			}
+		}
+		if !cached {
+			return nil, fmt.Errorf("%s: %w", what, ErrNotFound)
+		}
+		c.lookup.drop(key)
+	}
+}
+
+// lookupCache holds listings keyed by "kind:scope", e.g. "sources:12"
+type lookupCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]lookupEntry
+}
+
+type lookupEntry struct {
+	items   interface{}
+	expires time.Time
+}
+
+func newLookupCache(ttl time.Duration) *lookupCache {
+	return &lookupCache{ttl: ttl, entries: make(map[string]lookupEntry)}
+}
+
+// cachedList returns a fresh cached listing or fetches and stores a new one.
+// cached reports whether the listing came from the cache.
+func cachedList[T any](l *lookupCache, key string, fetch func() ([]T, error)) (items []T, cached bool, err error) {
+	l.mu.Lock()
+	entry, ok := l.entries[key]
+	l.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.items.([]T), true, nil
+	}
+
+	items, err = fetch()
+	if err != nil {
+		return nil, false, err
+	}
+	if l.ttl > 0 {
+		l.mu.Lock()
+		l.entries[key] = lookupEntry{items: items, expires: time.Now().Add(l.ttl)}
+		l.mu.Unlock()
+	}
+	return items, false, nil
+}
+
+func (l *lookupCache) drop(key string) {
+	l.mu.Lock()
+	delete(l.entries, key)
+	l.mu.Unlock()
+}
+
+func (l *lookupCache) clear() {
+	l.mu.Lock()
+	l.entries = make(map[string]lookupEntry)
+	l.mu.Unlock()
+}
+
+// invalidate drops the listings a non-GET request to path may change
+func (l *lookupCache) invalidate(method, path string) {
+	if method == http.MethodGet {
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	var kinds []string
+	switch parts[0] {
+	case "org":
+		if method == http.MethodDelete && (len(parts) == 2 || (len(parts) == 3 && parts[2] == "membership")) {
+			// Deleting or leaving an organization removes its projects and
+			// their sources, destinations and connections from view
+			l.clear()
+			return
+		}
+		kinds = []string{"orgs"}
+	case "projects":
+		switch {
+		case len(parts) == 3:
+			// Connection changes affect the sources' connection counts
+			kinds = []string{parts[2], "sources"}
+		case method == http.MethodDelete:
+			l.clear()
+			return
+		default:
+			kinds = []string{"projects"}
+		}
+	case "sources", "destinations":
+		// Deletes cascade to connections
+		kinds = []string{parts[0], "connections", "sources"}
+	case "connections":
+		kinds = []string{"connections", "sources"}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key := range l.entries {
+		for _, kind := range kinds {
+			if strings.HasPrefix(key, kind+":") || key == kind {
+				delete(l.entries, key)
+			}
+		}
+	}
+}
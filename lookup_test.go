@@ -0,0 +1,184 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// countingServer serves the emulator and counts GET requests per path
+type countingServer struct {
+	emu  *emulator.Server
+	mu   sync.Mutex
+	gets map[string]int
+}
+
+func (s *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		s.gets[r.URL.Path]++
+		s.mu.Unlock()
+	}
+	s.emu.ServeHTTP(w, r)
+}
+
+func (s *countingServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets[path]
+}
+
+func setupLookup(t *testing.T, opts ...volley.ClientOption) (*countingServer, *volley.Client, *httptest.Server) {
+	t.Helper()
+	backend := &countingServer{emu: emulator.New(nil), gets: make(map[string]int)}
+	server := httptest.NewServer(backend)
+	t.Cleanup(func() {
+		server.Close()
+		backend.emu.Close()
+	})
+	opts = append([]volley.ClientOption{volley.WithBaseURL(server.URL)}, opts...)
+	return backend, volley.NewClient("test-token", opts...), server
+}
+
+func TestFindSourceCaching(t *testing.T) {
+	backend, client, _ := setupLookup(t)
+	const path = "/api/projects/1/sources"
+
+	created, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		src, err := client.FindSourceBySlug(1, "stripe")
+		if err != nil {
+			t.Fatalf("FindSourceBySlug failed: %v", err)
+		}
+		if src.ID != created.ID {
+			t.Errorf("Expected source %d, got %d", created.ID, src.ID)
+		}
+	}
+	if n := backend.count(path); n != 1 {
+		t.Errorf("Expected repeated lookups to list sources once, got %d", n)
+	}
+
+	if src, err := client.FindSourceByIngestionID(1, created.IngestionID); err != nil || src.ID != created.ID {
+		t.Errorf("Expected to find source by ingestion ID, got %v, %v", src, err)
+	}
+
+	// An update through the client invalidates the cached listing
+	if _, err := client.UpdateSource(created.ID, volley.UpdateSourceRequest{Name: "stripe-live"}); err != nil {
+		t.Fatalf("UpdateSource failed: %v", err)
+	}
+	if _, err := client.FindSourceBySlug(1, "stripe-live"); err != nil {
+		t.Errorf("Expected the renamed source after invalidation, got %v", err)
+	}
+	if n := backend.count(path); n != 2 {
+		t.Errorf("Expected the update to cause one more listing, got %d", n)
+	}
+}
+
+func TestFindRefreshesOnMiss(t *testing.T) {
+	_, client, server := setupLookup(t)
+
+	if _, err := client.FindDestinationByName(1, "api"); !errors.Is(err, volley.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Created by another client, so this client's cache is not invalidated
+	other := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	if _, err := other.CreateDestination(1, volley.CreateDestinationRequest{Name: "api", URL: "https://api.example.com"}); err != nil {
+		t.Fatalf("CreateDestination failed: %v", err)
+	}
+	if _, err := client.FindDestinationByName(1, "api"); err != nil {
+		t.Errorf("Expected a miss on cached data to refresh the listing, got %v", err)
+	}
+}
+
+func TestFindTTL(t *testing.T) {
+	backend, client, _ := setupLookup(t, volley.WithLookupCacheTTL(20*time.Millisecond))
+
+	client.FindProjectByName("Default Project")
+	client.FindProjectByName("Default Project")
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.FindProjectByName("Default Project"); err != nil {
+		t.Fatalf("FindProjectByName failed: %v", err)
+	}
+	if n := backend.count("/api/projects"); n != 2 {
+		t.Errorf("Expected the listing to be refetched after the TTL, got %d listings", n)
+	}
+}
+
+func TestFindConnectionAndOrganization(t *testing.T) {
+	_, client, _ := setupLookup(t)
+
+	src, _ := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	dest, _ := client.CreateDestination(1, volley.CreateDestinationRequest{Name: "api", URL: "https://api.example.com"})
+	conn, err := client.CreateConnection(1, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID})
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	found, err := client.FindConnection(1, src.ID, dest.ID)
+	if err != nil || found.ID != conn.ID {
+		t.Errorf("Expected connection %d, got %v, %v", conn.ID, found, err)
+	}
+	if err := client.DeleteConnection(conn.ID); err != nil {
+		t.Fatalf("DeleteConnection failed: %v", err)
+	}
+	if _, err := client.FindConnection(1, src.ID, dest.ID); !errors.Is(err, volley.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+
+	org, err := client.FindOrganizationBySlug("default")
+	if err != nil || org.ID != 1 {
+		t.Errorf("Expected the default organization, got %v, %v", org, err)
+	}
+}
+
+func TestLeaveOrganizationInvalidatesLookups(t *testing.T) {
+	backend := &countingServer{emu: emulator.New(nil), gets: make(map[string]int)}
+	defer backend.emu.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The emulator's only member is the last owner, who cannot leave
+		if r.Method == http.MethodDelete && r.URL.Path == "/api/org/1/membership" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"message":"left organization"}`))
+			return
+		}
+		backend.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithOrganizationID(1))
+
+	if _, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "stripe"}); err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	lookup := func() {
+		if _, err := client.Projects.Default(); err != nil {
+			t.Fatalf("Projects.Default failed: %v", err)
+		}
+		if _, err := client.Sources.FindBySlug(1, "stripe"); err != nil {
+			t.Fatalf("FindBySlug failed: %v", err)
+		}
+	}
+	lookup()
+	lookup()
+	if err := client.Organizations.Leave(1); err != nil {
+		t.Fatalf("Leave failed: %v", err)
+	}
+	lookup()
+
+	if n := backend.count("/api/projects"); n != 2 {
+		t.Errorf("Expected the projects to be listed again after leaving, got %d listings", n)
+	}
+	if n := backend.count("/api/projects/1/sources"); n != 2 {
+		t.Errorf("Expected the sources to be listed again after leaving, got %d listings", n)
+	}
+}
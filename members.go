@@ -0,0 +1,121 @@
+package volley
+
+import "fmt"
+
+// ListMembers lists the members of an organization
+func (s *OrganizationsService) ListMembers(organizationID uint64) ([]Member, error) {
+	path := fmt.Sprintf("/api/org/%d/members", organizationID)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Members []Member `json:"members"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Members, nil
+}
+
+// UpdateMemberRole changes a member's role. An organization's last owner
+// cannot be demoted.
+func (s *OrganizationsService) UpdateMemberRole(memberID uint64, role Role) (*Member, error) {
+	path := fmt.Sprintf("/api/members/%d", memberID)
+	req := struct {
+		Role Role `json:"role"`
+	}{role}
+	resp, err := s.client.doRequest("PUT", path, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Member Member `json:"member"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Member, nil
+}
+
+// RemoveMember removes a member from their organization. An organization's
+// last owner cannot be removed.
+func (s *OrganizationsService) RemoveMember(memberID uint64) error {
+	path := fmt.Sprintf("/api/members/%d", memberID)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.parseResponse(resp, nil)
+}
+
+// Leave removes the caller from an organization. The last owner cannot leave;
+// transfer ownership with UpdateMemberRole or delete the organization instead.
+func (s *OrganizationsService) Leave(organizationID uint64) error {
+	path := fmt.Sprintf("/api/org/%d/membership", organizationID)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.parseResponse(resp, nil)
+}
+
+// InviteMemberRequest represents the request to invite someone to an organization
+type InviteMemberRequest struct {
+	Email string `json:"email"`
+	Role  Role   `json:"role"`
+}
+
+// InviteMember invites someone to an organization by email. They become a
+// member with the invite's role when they accept.
+func (s *OrganizationsService) InviteMember(organizationID uint64, req InviteMemberRequest) (*Invite, error) {
+	path := fmt.Sprintf("/api/org/%d/invites", organizationID)
+	resp, err := s.client.doRequest("POST", path, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Invite Invite `json:"invite"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Invite, nil
+}
+
+// ListInvites lists the pending invites of an organization
+func (s *OrganizationsService) ListInvites(organizationID uint64) ([]Invite, error) {
+	path := fmt.Sprintf("/api/org/%d/invites", organizationID)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Invites []Invite `json:"invites"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Invites, nil
+}
+
+// RevokeInvite revokes a pending invite
+func (s *OrganizationsService) RevokeInvite(inviteID uint64) error {
+	path := fmt.Sprintf("/api/invites/%d", inviteID)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.parseResponse(resp, nil)
+}
@@ -0,0 +1,84 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func TestOrganizationMembers(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	org, err := client.Organizations.Create(volley.CreateOrganizationRequest{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("Organizations.Create failed: %v", err)
+	}
+	members, err := client.Organizations.ListMembers(org.ID)
+	if err != nil || len(members) != 1 || members[0].Role != volley.RoleOwner {
+		t.Fatalf("Expected the creator to be the only owner, got %v (%v)", members, err)
+	}
+	owner := members[0]
+
+	invite, err := client.Organizations.InviteMember(org.ID, volley.InviteMemberRequest{Email: "dev@example.com", Role: volley.RoleAdmin})
+	if err != nil {
+		t.Fatalf("InviteMember failed: %v", err)
+	}
+	if invite.Role != volley.RoleAdmin || !invite.ExpiresAt.After(invite.CreatedAt) {
+		t.Errorf("Unexpected invite: %+v", invite)
+	}
+	var apiErr *volley.APIError
+	if _, err := client.Organizations.InviteMember(org.ID, volley.InviteMemberRequest{Email: "dev@example.com", Role: volley.RoleMember}); !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected a duplicate invite to conflict, got %v", err)
+	}
+	if _, err := client.Organizations.InviteMember(org.ID, volley.InviteMemberRequest{Email: "ops@example.com", Role: "superuser"}); err == nil {
+		t.Error("Expected an invalid role to be rejected")
+	}
+
+	if invites, err := client.Organizations.ListInvites(org.ID); err != nil || len(invites) != 1 {
+		t.Errorf("Expected 1 invite, got %v (%v)", invites, err)
+	}
+	if err := client.Organizations.RevokeInvite(invite.ID); err != nil {
+		t.Fatalf("RevokeInvite failed: %v", err)
+	}
+	if invites, err := client.Organizations.ListInvites(org.ID); err != nil || len(invites) != 0 {
+		t.Errorf("Expected no invites after revoking, got %v (%v)", invites, err)
+	}
+
+	// The last owner can neither be demoted nor leave
+	if _, err := client.Organizations.UpdateMemberRole(owner.ID, volley.RoleAdmin); err == nil {
+		t.Error("Expected demoting the last owner to fail")
+	}
+	if err := client.Organizations.Leave(org.ID); err == nil {
+		t.Error("Expected the last owner to be unable to leave")
+	}
+	if err := client.Organizations.RemoveMember(owner.ID); err == nil {
+		t.Error("Expected removing the last owner to fail")
+	}
+
+	if _, err := client.Organizations.FindBySlug("acme"); err != nil {
+		t.Fatalf("FindBySlug failed: %v", err)
+	}
+	updated, err := client.Organizations.Update(org.ID, volley.UpdateOrganizationRequest{Name: "Acme Corp"})
+	if err != nil || updated.Name != "Acme Corp" || updated.Slug != "acme-corp" {
+		t.Errorf("Expected the organization to be renamed, got %v (%v)", updated, err)
+	}
+	if found, err := client.Organizations.FindBySlug("acme-corp"); err != nil || found.ID != org.ID {
+		t.Errorf("Expected the update to invalidate the lookup cache, got %v (%v)", found, err)
+	}
+
+	if err := client.Organizations.Delete(org.ID); err != nil {
+		t.Fatalf("Organizations.Delete failed: %v", err)
+	}
+	if _, err := client.Organizations.ListMembers(org.ID); !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected the deleted organization to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package volley
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestMetrics describes one HTTP request made by the client
+type RequestMetrics struct {
+	// Operation is the method and path with IDs replaced by placeholders,
+	// e.g. "GET /api/projects/:id/sources" or "POST /hook/:ingestion_id"
+	Operation string
+	// StatusCode is zero when the request failed without a response
+	StatusCode int
+	// Duration runs from sending the request until the response body is closed
+	Duration      time.Duration
+	RequestBytes  int64
+	ResponseBytes int64
+	// Retries is the number of earlier attempts of the same call; an attempt
+	// with Retries > 0 is a retry
+	Retries int
+	// Err is the transport error, if any
+	Err error
+}
+
+// Metrics receives a RequestMetrics for every API request and webhook sent.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveRequest(RequestMetrics)
+}
+
+// WithMetrics reports every request to m. Requests with a response are
+// reported when their body is closed, which the client always does.
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// send performs req, reporting it to the configured Metrics
+func (c *Client) send(req *http.Request, operation string, requestBytes int64, retries int) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if c.metrics == nil {
+		return resp, err
+	}
+
+	m := RequestMetrics{Operation: operation, RequestBytes: requestBytes, Retries: retries}
+	if err != nil {
+		m.Duration = time.Since(start)
+		m.Err = err
+		c.metrics.ObserveRequest(m)
+		return nil, err
+	}
+
+	m.StatusCode = resp.StatusCode
+	resp.Body = &meteredBody{ReadCloser: resp.Body, report: func(n int64) {
+		m.Duration = time.Since(start)
+		m.ResponseBytes = n
+		c.metrics.ObserveRequest(m)
+	}}
+	return resp, nil
+}
+
+// meteredBody counts the bytes read from a response body and reports them once on Close
+type meteredBody struct {
+	io.ReadCloser
+	n      int64
+	once   sync.Once
+	report func(n int64)
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.report(b.n) })
+	return err
+}
+
+// operationName replaces the numeric segments of path with ":id"
+func operationName(method, path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part != "" && strings.Trim(part, "0123456789") == "" {
+			parts[i] = ":id"
+		}
+	}
+	return method + " " + strings.Join(parts, "/")
+}
+
+// DefaultDurationBuckets are the default histogram upper bounds of MemoryMetrics
+var DefaultDurationBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// OperationStats are cumulative statistics for one operation
+type OperationStats struct {
+	Operation string `json:"operation"`
+	Requests  uint64 `json:"requests"`
+	// Errors counts requests that failed without a response
+	Errors        uint64         `json:"errors"`
+	StatusCodes   map[int]uint64 `json:"status_codes"`
+	Retries       uint64         `json:"retries"`
+	RequestBytes  uint64         `json:"request_bytes"`
+	ResponseBytes uint64         `json:"response_bytes"`
+	// DurationBuckets count requests at or under each bound, cumulatively
+	DurationBuckets []DurationBucket `json:"duration_buckets"`
+	DurationSum     time.Duration    `json:"duration_sum"`
+}
+
+// DurationBucket is a cumulative histogram bucket
+type DurationBucket struct {
+	UpperBound time.Duration `json:"le"`
+	Count      uint64        `json:"count"`
+}
+
+// MemoryMetrics is a Metrics implementation keeping counters and duration
+// histograms per operation in memory
+type MemoryMetrics struct {
+	buckets []time.Duration
+	mu      sync.Mutex
+	ops     map[string]*OperationStats
+}
+
+// NewMemoryMetrics creates a MemoryMetrics with the given histogram bounds
+// (default DefaultDurationBuckets)
+func NewMemoryMetrics(buckets ...time.Duration) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return &MemoryMetrics{buckets: buckets, ops: make(map[string]*OperationStats)}
+}
+
+// ObserveRequest records a request
+func (m *MemoryMetrics) ObserveRequest(r RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[r.Operation]
+	if !ok {
+		stats = &OperationStats{Operation: r.Operation, StatusCodes: make(map[int]uint64)}
+		for _, bound := range m.buckets {
+			stats.DurationBuckets = append(stats.DurationBuckets, DurationBucket{UpperBound: bound})
+		}
+		m.ops[r.Operation] = stats
+	}
+
+	stats.Requests++
+	if r.StatusCode == 0 {
+		stats.Errors++
+	} else {
+		stats.StatusCodes[r.StatusCode]++
+	}
+	if r.Retries > 0 {
+		stats.Retries++
+	}
+	stats.RequestBytes += uint64(r.RequestBytes)
+	stats.ResponseBytes += uint64(r.ResponseBytes)
+	for i := range stats.DurationBuckets {
+		if r.Duration <= stats.DurationBuckets[i].UpperBound {
+			stats.DurationBuckets[i].Count++
+		}
+	}
+	stats.DurationSum += r.Duration
+}
+
+// Snapshot returns a copy of the statistics, sorted by operation
+func (m *MemoryMetrics) Snapshot() []OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]OperationStats, 0, len(m.ops))
+	for _, stats := range m.ops {
+		s := *stats
+		s.StatusCodes = make(map[int]uint64, len(stats.StatusCodes))
+		for code, n := range stats.StatusCodes {
+			s.StatusCodes[code] = n
+		}
+		s.DurationBuckets = append([]DurationBucket(nil), stats.DurationBuckets...)
+		snapshot = append(snapshot, s)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Operation < snapshot[j].Operation })
+	return snapshot
+}
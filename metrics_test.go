@@ -0,0 +1,154 @@
+package volley_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// recordingMetrics keeps every observed request
+type recordingMetrics struct {
+	mu       sync.Mutex
+	requests []volley.RequestMetrics
+}
+
+func (m *recordingMetrics) ObserveRequest(r volley.RequestMetrics) {
+	m.mu.Lock()
+	m.requests = append(m.requests, r)
+	m.mu.Unlock()
+}
+
+func TestMetricsObserveRequests(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	metrics := &recordingMetrics{}
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithMetrics(metrics))
+
+	src, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+	if _, err := client.GetSource(src.ID); err != nil {
+		t.Fatalf("GetSource failed: %v", err)
+	}
+	if _, err := client.GetSource(999); err == nil {
+		t.Fatal("Expected GetSource of a missing source to fail")
+	}
+	if _, err := client.SendWebhook(src.IngestionID, map[string]string{"type": "test"}); err != nil {
+		t.Fatalf("SendWebhook failed: %v", err)
+	}
+
+	expected := []struct {
+		operation string
+		status    int
+	}{
+		{"POST /api/projects/:id/sources", http.StatusCreated},
+		{"GET /api/sources/:id", http.StatusOK},
+		{"GET /api/sources/:id", http.StatusNotFound},
+		{"POST /hook/:ingestion_id", http.StatusAccepted},
+	}
+	if len(metrics.requests) != len(expected) {
+		t.Fatalf("Expected %d observed requests, got %d: %+v", len(expected), len(metrics.requests), metrics.requests)
+	}
+	for i, want := range expected {
+		got := metrics.requests[i]
+		if got.Operation != want.operation || got.StatusCode != want.status {
+			t.Errorf("Request %d: expected %s %d, got %s %d", i, want.operation, want.status, got.Operation, got.StatusCode)
+		}
+		if got.ResponseBytes == 0 {
+			t.Errorf("Request %d: expected response bytes to be counted", i)
+		}
+		if got.Duration <= 0 {
+			t.Errorf("Request %d: expected a duration", i)
+		}
+	}
+	if metrics.requests[0].RequestBytes == 0 || metrics.requests[1].RequestBytes != 0 {
+		t.Errorf("Expected request bytes only for bodies, got %d and %d", metrics.requests[0].RequestBytes, metrics.requests[1].RequestBytes)
+	}
+}
+
+func TestMetricsTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	metrics := volley.NewMemoryMetrics()
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithMetrics(metrics))
+	if _, err := client.ListProjects(); err == nil {
+		t.Fatal("Expected ListProjects against a closed server to fail")
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Operation != "GET /api/projects" {
+		t.Fatalf("Expected one operation, got %+v", snapshot)
+	}
+	if snapshot[0].Requests != 1 || snapshot[0].Errors != 1 || len(snapshot[0].StatusCodes) != 0 {
+		t.Errorf("Expected one failed request without a status, got %+v", snapshot[0])
+	}
+}
+
+func TestMetricsCountRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"projects":[]}`))
+	}))
+	defer server.Close()
+
+	metrics := volley.NewMemoryMetrics()
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithMetrics(metrics),
+		volley.WithRetryPolicy(volley.RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}))
+	if _, err := client.Projects.List(); err != nil {
+		t.Fatalf("Projects.List failed: %v", err)
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Requests != 4 || snapshot[0].Retries != 3 {
+		t.Errorf("Expected 4 requests and 3 retries, got %+v", snapshot)
+	}
+}
+
+func TestMemoryMetricsSnapshot(t *testing.T) {
+	metrics := volley.NewMemoryMetrics(100*time.Millisecond, 10*time.Millisecond)
+	metrics.ObserveRequest(volley.RequestMetrics{Operation: "GET /api/projects", StatusCode: 200, Duration: 5 * time.Millisecond, ResponseBytes: 10})
+	metrics.ObserveRequest(volley.RequestMetrics{Operation: "GET /api/projects", StatusCode: 200, Duration: 50 * time.Millisecond, ResponseBytes: 20})
+	metrics.ObserveRequest(volley.RequestMetrics{Operation: "GET /api/projects", StatusCode: 500, Duration: time.Second, Retries: 2})
+	metrics.ObserveRequest(volley.RequestMetrics{Operation: "DELETE /api/sources/:id", StatusCode: 200, RequestBytes: 0})
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].Operation != "DELETE /api/sources/:id" {
+		t.Fatalf("Expected operations sorted by name, got %+v", snapshot)
+	}
+
+	projects := snapshot[1]
+	if projects.Requests != 3 || projects.StatusCodes[200] != 2 || projects.StatusCodes[500] != 1 {
+		t.Errorf("Unexpected counts: %+v", projects)
+	}
+	if projects.Retries != 1 || projects.ResponseBytes != 30 {
+		t.Errorf("Expected 1 retry and 30 response bytes, got %d and %d", projects.Retries, projects.ResponseBytes)
+	}
+	buckets := projects.DurationBuckets
+	if len(buckets) != 2 || buckets[0].UpperBound != 10*time.Millisecond || buckets[0].Count != 1 || buckets[1].Count != 2 {
+		t.Errorf("Expected sorted cumulative buckets [1 2], got %+v", buckets)
+	}
+	if projects.DurationSum != 1055*time.Millisecond {
+		t.Errorf("Expected duration sum 1.055s, got %v", projects.DurationSum)
+	}
+
+	// Snapshots are copies
+	snapshot[1].StatusCodes[200] = 100
+	if metrics.Snapshot()[1].StatusCodes[200] != 2 {
+		t.Error("Expected the snapshot to be independent of the metrics")
+	}
+}
@@ -1,8 +1,13 @@
 package volley
 
-// ListOrganizations lists all organizations the user has access to
-func (c *Client) ListOrganizations() ([]Organization, error) {
-	resp, err := c.doRequest("GET", "/api/org/list", nil, nil)
+import "fmt"
+
+// OrganizationsService lists, gets and creates organizations
+type OrganizationsService service
+
+// List lists all organizations the user has access to
+func (s *OrganizationsService) List() ([]Organization, error) {
+	resp, err := s.client.doRequest("GET", "/api/org/list", nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -10,35 +15,45 @@ func (c *Client) ListOrganizations() ([]Organization, error) {
 	var result struct {
 		Organizations []Organization `json:"organizations"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return result.Organizations, nil
 }
 
-// GetOrganization gets the current organization
-// If organizationID is provided, it will be used; otherwise, the first accessible organization is returned
-func (c *Client) GetOrganization(organizationID *uint64) (*Organization, error) {
+// Current gets the client's organization, or the first accessible
+// organization if none is set
+func (s *OrganizationsService) Current() (*Organization, error) {
+	return s.get(nil)
+}
+
+// Get gets an organization by ID
+func (s *OrganizationsService) Get(organizationID uint64) (*Organization, error) {
+	return s.get(&organizationID)
+}
+
+// get gets the organization, temporarily switching to organizationID if set
+func (s *OrganizationsService) get(organizationID *uint64) (*Organization, error) {
 	// Temporarily set organization ID if provided
-	originalOrgID := c.organizationID
+	originalOrgID := s.client.organizationID
 	if organizationID != nil {
-		c.organizationID = organizationID
+		s.client.organizationID = organizationID
 	}
 
-	resp, err := c.doRequest("GET", "/api/org", nil, nil)
+	resp, err := s.client.doRequest("GET", "/api/org", nil, nil)
 	if err != nil {
-		c.organizationID = originalOrgID
+		s.client.organizationID = originalOrgID
 		return nil, err
 	}
 
 	var org Organization
-	if err := c.parseResponse(resp, &org); err != nil {
-		c.organizationID = originalOrgID
+	if err := s.client.parseResponse(resp, &org); err != nil {
+		s.client.organizationID = originalOrgID
 		return nil, err
 	}
 
-	c.organizationID = originalOrgID
+	s.client.organizationID = originalOrgID
 	return &org, nil
 }
 
@@ -47,18 +62,50 @@ type CreateOrganizationRequest struct {
 	Name string `json:"name"`
 }
 
-// CreateOrganization creates a new organization
-func (c *Client) CreateOrganization(req CreateOrganizationRequest) (*Organization, error) {
-	resp, err := c.doRequest("POST", "/api/org", req, nil)
+// Create creates a new organization
+func (s *OrganizationsService) Create(req CreateOrganizationRequest) (*Organization, error) {
+	resp, err := s.client.doRequest("POST", "/api/org", req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var org Organization
+	if err := s.client.parseResponse(resp, &org); err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// UpdateOrganizationRequest represents the request to update an organization
+type UpdateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// Update updates an organization's name
+func (s *OrganizationsService) Update(organizationID uint64, req UpdateOrganizationRequest) (*Organization, error) {
+	path := fmt.Sprintf("/api/org/%d", organizationID)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var org Organization
-	if err := c.parseResponse(resp, &org); err != nil {
+	if err := s.client.parseResponse(resp, &org); err != nil {
 		return nil, err
 	}
 
 	return &org, nil
 }
 
+// Delete deletes an organization and all of its projects. Only owners can
+// delete an organization.
+func (s *OrganizationsService) Delete(organizationID uint64) error {
+	path := fmt.Sprintf("/api/org/%d", organizationID)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.parseResponse(resp, nil)
+}
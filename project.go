@@ -0,0 +1,228 @@
+package volley
+
+// WithProjectID sets the project used by project handles created with Project(0)
+func WithProjectID(projectID uint64) ClientOption {
+	return func(c *Client) {
+		c.projectID = projectID
+	}
+}
+
+// SetProjectID sets the project used by project handles created with Project(0)
+func (c *Client) SetProjectID(projectID uint64) {
+	c.projectID = projectID
+}
+
+// ClearProjectID clears the client's project, so Project(0) handles use the
+// organization's default project
+func (c *Client) ClearProjectID() {
+	c.projectID = 0
+}
+
+// ProjectID returns the client's project, or zero if none is set
+func (c *Client) ProjectID() uint64 {
+	return c.projectID
+}
+
+// ProjectScope is a handle on one project whose methods omit the project ID:
+//
+//	p := client.Project(0) // the client's project or the organization's default
+//	sources, err := p.Sources.List()
+//	events, err := p.Events.List(&volley.ListEventsOptions{Status: "failed"})
+type ProjectScope struct {
+	client *Client
+	id     uint64
+
+	Sources          *ProjectSources
+	Destinations     *ProjectDestinations
+	Connections      *ProjectConnections
+	Events           *ProjectEvents
+	DeliveryAttempts *ProjectDeliveryAttempts
+}
+
+// Project returns a handle scoped to projectID. A projectID of zero uses the
+// client's project (see WithProjectID) or, if none is set, the current
+// organization's default project, resolved on each call.
+func (c *Client) Project(projectID uint64) *ProjectScope {
+	p := &ProjectScope{client: c, id: projectID}
+	p.Sources = &ProjectSources{p}
+	p.Destinations = &ProjectDestinations{p}
+	p.Connections = &ProjectConnections{p}
+	p.Events = &ProjectEvents{p}
+	p.DeliveryAttempts = &ProjectDeliveryAttempts{p}
+	return p
+}
+
+// ID returns the project ID the handle resolves to
+func (p *ProjectScope) ID() (uint64, error) {
+	if p.id != 0 {
+		return p.id, nil
+	}
+	if p.client.projectID != 0 {
+		return p.client.projectID, nil
+	}
+	project, err := p.client.Projects.Default()
+	if err != nil {
+		return 0, err
+	}
+	return project.ID, nil
+}
+
+// Export returns the project's topology
+func (p *ProjectScope) Export() (*TopologySpec, error) {
+	id, err := p.ID()
+	if err != nil {
+		return nil, err
+	}
+	return p.client.ExportProject(id)
+}
+
+// PlanTopology computes the changes needed for the project to match spec
+func (p *ProjectScope) PlanTopology(spec *TopologySpec, opts *TopologyOptions) (*TopologyPlan, error) {
+	id, err := p.ID()
+	if err != nil {
+		return nil, err
+	}
+	return p.client.PlanTopology(id, spec, opts)
+}
+
+// Drift compares the project against spec
+func (p *ProjectScope) Drift(spec *TopologySpec) (*DriftReport, error) {
+	id, err := p.ID()
+	if err != nil {
+		return nil, err
+	}
+	return p.client.Drift(id, spec)
+}
+
+// ProjectSources manages the sources of a project
+type ProjectSources struct {
+	project *ProjectScope
+}
+
+// List lists the project's sources
+func (s *ProjectSources) List() ([]Source, error) {
+	id, err := s.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return s.project.client.Sources.List(id)
+}
+
+// Create creates a source in the project
+func (s *ProjectSources) Create(req CreateSourceRequest) (*Source, error) {
+	id, err := s.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return s.project.client.Sources.Create(id, req)
+}
+
+// FindBySlug returns the project's source with the given slug
+func (s *ProjectSources) FindBySlug(slug string) (*Source, error) {
+	id, err := s.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return s.project.client.Sources.FindBySlug(id, slug)
+}
+
+// FindByIngestionID returns the project's source with the given ingestion ID
+func (s *ProjectSources) FindByIngestionID(ingestionID string) (*Source, error) {
+	id, err := s.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return s.project.client.Sources.FindByIngestionID(id, ingestionID)
+}
+
+// ProjectDestinations manages the destinations of a project
+type ProjectDestinations struct {
+	project *ProjectScope
+}
+
+// List lists the project's destinations
+func (d *ProjectDestinations) List() ([]Destination, error) {
+	id, err := d.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return d.project.client.Destinations.List(id)
+}
+
+// Create creates a destination in the project
+func (d *ProjectDestinations) Create(req CreateDestinationRequest) (*Destination, error) {
+	id, err := d.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return d.project.client.Destinations.Create(id, req)
+}
+
+// FindByName returns the project's destination with the given name
+func (d *ProjectDestinations) FindByName(name string) (*Destination, error) {
+	id, err := d.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return d.project.client.Destinations.FindByName(id, name)
+}
+
+// ProjectConnections manages the connections of a project
+type ProjectConnections struct {
+	project *ProjectScope
+}
+
+// List lists the project's connections
+func (cs *ProjectConnections) List() ([]Connection, error) {
+	id, err := cs.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return cs.project.client.Connections.List(id)
+}
+
+// Create creates a connection in the project
+func (cs *ProjectConnections) Create(req CreateConnectionRequest) (*Connection, error) {
+	id, err := cs.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return cs.project.client.Connections.Create(id, req)
+}
+
+// Find returns the project's connection from a source to a destination
+func (cs *ProjectConnections) Find(sourceID, destinationID uint64) (*Connection, error) {
+	id, err := cs.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return cs.project.client.Connections.Find(id, sourceID, destinationID)
+}
+
+// ProjectEvents lists the events of a project
+type ProjectEvents struct {
+	project *ProjectScope
+}
+
+// List lists the project's events with optional filters
+func (e *ProjectEvents) List(opts *ListEventsOptions) (*ListEventsResponse, error) {
+	id, err := e.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return e.project.client.Events.List(id, opts)
+}
+
+// ProjectDeliveryAttempts lists the delivery attempts of a project
+type ProjectDeliveryAttempts struct {
+	project *ProjectScope
+}
+
+// List lists the project's delivery attempts with optional filters
+func (a *ProjectDeliveryAttempts) List(opts *ListDeliveryAttemptsOptions) (*ListDeliveryAttemptsResponse, error) {
+	id, err := a.project.ID()
+	if err != nil {
+		return nil, err
+	}
+	return a.project.client.DeliveryAttempts.List(id, opts)
+}
@@ -0,0 +1,81 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func TestProjectScope(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	// Without a project set, the organization's default project is used
+	p := client.Project(0)
+	if id, err := p.ID(); err != nil || id != 1 {
+		t.Fatalf("Expected the default project 1, got %d (%v)", id, err)
+	}
+	src, err := p.Sources.Create(volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	dest, err := p.Destinations.Create(volley.CreateDestinationRequest{Name: "api", URL: "https://example.com/hooks"})
+	if err != nil {
+		t.Fatalf("Destinations.Create failed: %v", err)
+	}
+	if _, err := p.Connections.Create(volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID}); err != nil {
+		t.Fatalf("Connections.Create failed: %v", err)
+	}
+	if conns, err := p.Connections.List(); err != nil || len(conns) != 1 {
+		t.Errorf("Expected 1 connection, got %d (%v)", len(conns), err)
+	}
+	if found, err := p.Sources.FindBySlug("stripe"); err != nil || found.ID != src.ID {
+		t.Errorf("Expected FindBySlug to find the source, got %v (%v)", found, err)
+	}
+	if events, err := p.Events.List(nil); err != nil || events.Total != 0 {
+		t.Errorf("Expected no events, got %v (%v)", events, err)
+	}
+
+	// The client's project takes precedence over the default
+	other, err := client.CreateProject(volley.CreateProjectRequest{Name: "Staging"})
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	client.SetProjectID(other.ID)
+	if sources, err := client.Project(0).Sources.List(); err != nil || len(sources) != 0 {
+		t.Errorf("Expected no sources in the client's project, got %d (%v)", len(sources), err)
+	}
+	if sources, err := client.Project(1).Sources.List(); err != nil || len(sources) != 1 {
+		t.Errorf("Expected an explicit project to be used, got %d sources (%v)", len(sources), err)
+	}
+
+	scoped := volley.NewClient("test-token", volley.WithBaseURL(server.URL), volley.WithProjectID(other.ID))
+	if id, _ := scoped.Project(0).ID(); id != other.ID {
+		t.Errorf("Expected WithProjectID to set project %d, got %d", other.ID, id)
+	}
+}
+
+func TestProjectScopeNoDefault(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	org, err := client.CreateOrganization(volley.CreateOrganizationRequest{Name: "Empty"})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	client.SetOrganizationID(org.ID)
+
+	if _, err := client.Project(0).Sources.List(); !errors.Is(err, volley.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without a default project, got %v", err)
+	}
+}
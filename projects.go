@@ -2,9 +2,12 @@ package volley
 
 import "fmt"
 
-// ListProjects lists all projects in the current organization
-func (c *Client) ListProjects() ([]Project, error) {
-	resp, err := c.doRequest("GET", "/api/projects", nil, nil)
+// ProjectsService manages the projects of the current organization
+type ProjectsService service
+
+// List lists all projects in the current organization
+func (s *ProjectsService) List() ([]Project, error) {
+	resp, err := s.client.doRequest("GET", "/api/projects", nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -12,7 +15,7 @@ func (c *Client) ListProjects() ([]Project, error) {
 	var result struct {
 		Projects []Project `json:"projects"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -25,9 +28,9 @@ type CreateProjectRequest struct {
 	IsDefault bool   `json:"is_default,omitempty"`
 }
 
-// CreateProject creates a new project
-func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
-	resp, err := c.doRequest("POST", "/api/projects", req, nil)
+// Create creates a new project
+func (s *ProjectsService) Create(req CreateProjectRequest) (*Project, error) {
+	resp, err := s.client.doRequest("POST", "/api/projects", req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +38,7 @@ func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
 	var result struct {
 		Project Project `json:"project"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -47,10 +50,10 @@ type UpdateProjectRequest struct {
 	Name string `json:"name"`
 }
 
-// UpdateProject updates a project's name
-func (c *Client) UpdateProject(projectID uint64, req UpdateProjectRequest) (*Project, error) {
+// Update updates a project's name
+func (s *ProjectsService) Update(projectID uint64, req UpdateProjectRequest) (*Project, error) {
 	path := fmt.Sprintf("/api/projects/%d", projectID)
-	resp, err := c.doRequest("PUT", path, req, nil)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -58,39 +61,20 @@ func (c *Client) UpdateProject(projectID uint64, req UpdateProjectRequest) (*Pro
 	var result struct {
 		Project Project `json:"project"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Project, nil
 }
 
-// DeleteProject deletes a project
-func (c *Client) DeleteProject(projectID uint64) error {
+// Delete deletes a project
+func (s *ProjectsService) Delete(projectID uint64) error {
 	path := fmt.Sprintf("/api/projects/%d", projectID)
-	resp, err := c.doRequest("DELETE", path, nil, nil)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	return c.parseResponse(resp, nil)
+	return s.client.parseResponse(resp, nil)
 }
-
-// GetConnections lists all connections in a project
-func (c *Client) GetConnections(projectID uint64) ([]Connection, error) {
-	path := fmt.Sprintf("/api/projects/%d/connections", projectID)
-	resp, err := c.doRequest("GET", path, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result struct {
-		Connections []Connection `json:"connections"`
-	}
-	if err := c.parseResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return result.Connections, nil
-}
-
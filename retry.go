@@ -0,0 +1,81 @@
+package volley
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryBackoff is the delay before the first retry when RetryPolicy.Backoff is unset
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// RetryPolicy retries requests that failed for transient reasons. Rate
+// limited requests (429) are always retried; transport errors and 502, 503
+// and 504 responses are retried for GET, PUT and DELETE, which are safe to
+// repeat. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int `json:"max_retries" yaml:"max_retries"`
+	// Backoff is the delay before the first retry, doubling after each
+	// retry (default DefaultRetryBackoff). A Retry-After header takes precedence.
+	Backoff time.Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// MaxBackoff caps the delay between retries. Zero means no cap.
+	MaxBackoff time.Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+}
+
+// WithRetryPolicy retries failed requests according to p
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request, leaving any HTTP client
+// set with WithHTTPClient otherwise unchanged
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+// retryable reports whether a request may be retried after it failed with
+// status, where a status of zero is a transport error
+func (p RetryPolicy) retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case 0, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	}
+	return false
+}
+
+// delay returns how long to wait before retry n (starting at zero), honoring
+// a Retry-After header in seconds
+func (p RetryPolicy) delay(n int, resp *http.Response) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	// Double without shifting past the largest duration, which would wrap to
+	// a negative delay and skip both the wait and MaxBackoff
+	d := backoff
+	for i := 0; i < n && d <= math.MaxInt64/2; i++ {
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+		d *= 2
+	}
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			d = time.Duration(secs) * time.Second
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
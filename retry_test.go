@@ -0,0 +1,134 @@
+package volley_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// flakyServer fails the first failures requests with status
+type flakyServer struct {
+	mu       sync.Mutex
+	status   int
+	failures int
+	requests int
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.requests <= s.failures {
+		w.WriteHeader(s.status)
+		w.Write([]byte(`{"error": "try again"}`))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"projects": []}`))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		requests int
+	}{
+		{"GET retried on 503", http.MethodGet, http.StatusServiceUnavailable, 3},
+		{"POST retried on 429", http.MethodPost, http.StatusTooManyRequests, 3},
+		{"POST not retried on 503", http.MethodPost, http.StatusServiceUnavailable, 1},
+		{"GET not retried on 500", http.MethodGet, http.StatusInternalServerError, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &flakyServer{status: tt.status, failures: 2}
+			server := httptest.NewServer(backend)
+			defer server.Close()
+
+			metrics := &recordingMetrics{}
+			client := volley.NewClient("test-token",
+				volley.WithBaseURL(server.URL),
+				volley.WithRetryPolicy(volley.RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}),
+				volley.WithMetrics(metrics),
+			)
+
+			var err error
+			if tt.method == http.MethodGet {
+				_, err = client.ListProjects()
+			} else {
+				_, err = client.CreateProject(volley.CreateProjectRequest{Name: "retry"})
+			}
+
+			if backend.requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, backend.requests)
+			}
+			if retried := tt.requests > 1; retried != (err == nil) {
+				t.Errorf("Expected success only after retries, got %v", err)
+			}
+			if last := metrics.requests[len(metrics.requests)-1]; last.Retries != tt.requests-1 {
+				t.Errorf("Expected the last request to report %d retries, got %d", tt.requests-1, last.Retries)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyGivesUp(t *testing.T) {
+	backend := &flakyServer{status: http.StatusBadGateway, failures: 10}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := volley.NewClient("test-token",
+		volley.WithBaseURL(server.URL),
+		volley.WithRetryPolicy(volley.RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}),
+	)
+	if _, err := client.ListProjects(); err == nil {
+		t.Fatal("Expected ListProjects to fail after exhausting retries")
+	}
+	if backend.requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", backend.requests)
+	}
+}
+
+func TestRetryPolicyBackoffOverflow(t *testing.T) {
+	backend := &flakyServer{status: http.StatusServiceUnavailable, failures: 2}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	// Doubling this backoff overflows; each retry must still wait MaxBackoff
+	client := volley.NewClient("test-token",
+		volley.WithBaseURL(server.URL),
+		volley.WithRetryPolicy(volley.RetryPolicy{MaxRetries: 2, Backoff: 1 << 62, MaxBackoff: 50 * time.Millisecond}),
+	)
+	start := time.Now()
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected two capped waits of 50ms, took %v", elapsed)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+	client := volley.NewClient("test-token",
+		volley.WithBaseURL(server.URL),
+		volley.WithHTTPClient(httpClient),
+		volley.WithTimeout(20*time.Millisecond),
+	)
+	if _, err := client.ListProjects(); err == nil {
+		t.Error("Expected the request to time out")
+	}
+	if httpClient.Timeout != 0 {
+		t.Error("Expected WithTimeout to leave the caller's HTTP client unchanged")
+	}
+}
@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/volleyhq/volley-go"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadEvents parses events saved as JSON or YAML. It accepts the output of
+// "volley events list -o json" or "-o yaml" (a volley.ListEventsResponse), a
+// list of events or a single event.
+func LoadEvents(data []byte) ([]volley.Event, error) {
+	// YAML is a superset of JSON; round-trip through JSON to use the json tags
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+
+	switch v := doc.(type) {
+	case []interface{}:
+		var events []volley.Event
+		if err := json.Unmarshal(encoded, &events); err != nil {
+			return nil, fmt.Errorf("failed to parse events: %w", err)
+		}
+		return events, nil
+	case map[string]interface{}:
+		if _, ok := v["requests"]; ok {
+			var list volley.ListEventsResponse
+			if err := json.Unmarshal(encoded, &list); err != nil {
+				return nil, fmt.Errorf("failed to parse events: %w", err)
+			}
+			return list.Requests, nil
+		}
+		var ev volley.Event
+		if err := json.Unmarshal(encoded, &ev); err != nil {
+			return nil, fmt.Errorf("failed to parse events: %w", err)
+		}
+		return []volley.Event{ev}, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to parse events: expected a list of events or an event")
+	}
+}
+
+// LoadEventsFile reads and parses events from a JSON or YAML file
+func LoadEventsFile(path string) ([]volley.Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return LoadEvents(data)
+}
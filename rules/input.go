@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// input is an event being evaluated. Its body is decoded on first use.
+type input struct {
+	ev      volley.Event
+	decoded bool
+	body    interface{}
+	err     error
+}
+
+func newInput(ev volley.Event) *input {
+	return &input{ev: ev}
+}
+
+// json returns the decoded body. Numbers are kept as json.Number so they
+// compare in their original form.
+func (in *input) json() (interface{}, error) {
+	if !in.decoded {
+		in.decoded = true
+		dec := json.NewDecoder(strings.NewReader(in.ev.RawBody))
+		dec.UseNumber()
+		if err := dec.Decode(&in.body); err != nil {
+			in.err = errors.New("body is not valid JSON")
+		} else if dec.More() {
+			in.err = errors.New("body is not valid JSON: unexpected data after the value")
+		}
+	}
+	return in.body, in.err
+}
+
+// lookup returns the text form of a header or body value and whether it exists
+func (in *input) lookup(target volley.RuleTarget, path string) (string, bool, error) {
+	if target == volley.TargetHeader {
+		value, ok := in.headerValue(path)
+		return value, ok, nil
+	}
+	body, err := in.json()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := lookupPath(body, path)
+	if !ok {
+		return "", false, nil
+	}
+	text, err := textOf(value)
+	return text, true, err
+}
+
+// headerValue returns the first value of a header, matching its name
+// case-insensitively
+func (in *input) headerValue(name string) (string, bool) {
+	for key, value := range in.ev.Headers {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case []interface{}:
+			if len(v) > 0 {
+				return fmt.Sprint(v[0]), true
+			}
+		}
+	}
+	return "", false
+}
+
+func (in *input) header(name string) string {
+	value, _ := in.headerValue(name)
+	return value
+}
+
+// headers returns the first value of each header
+func (in *input) headers() map[string]string {
+	headers := make(map[string]string, len(in.ev.Headers))
+	for name := range in.ev.Headers {
+		headers[name] = in.header(name)
+	}
+	return headers
+}
+
+// lookupPath follows a dot-separated path through objects and, with numeric
+// segments, arrays
+func lookupPath(value interface{}, path string) (interface{}, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setPath sets a dot-separated path in out, creating objects along the way.
+// Transform.Validate rejects targets nested in one another, so no value is
+// ever replaced by an object.
+func setPath(out map[string]interface{}, path string, value interface{}) {
+	segments := strings.Split(path, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := out[segment].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			out[segment] = next
+		}
+		out = next
+	}
+	out[segments[len(segments)-1]] = value
+}
+
+// textOf returns a body value's JSON text, with strings unquoted
+func textOf(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,260 @@
+// Package rules evaluates connection filters and transforms locally, so they
+// can be tried against real traffic before a connection uses them.
+//
+// A Rule is compiled from volley.ConnectionRules and evaluated against events
+// fetched with Events.List or loaded from a file with LoadEvents. Test reports
+// which events match, what their transformed bodies would be and any event
+// that could not be evaluated, such as one whose body is not JSON:
+//
+//	rule, err := rules.Compile(volley.ConnectionRules{Filters: filters, Transform: transform})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	events, err := client.Events.List(projectID, &volley.ListEventsOptions{SourceID: &sourceID})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	report := rule.Test(events.Requests)
+//	fmt.Printf("%d of %d events match\n", report.Matched, report.Total)
+//
+// The emulator delivers events through connections with the same evaluation.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// Rule is a compiled set of connection filters and an optional transform.
+// The retry strategy does not affect evaluation.
+type Rule struct {
+	filters   []filter
+	transform *volley.Transform
+	template  *template.Template
+}
+
+type filter struct {
+	volley.FilterRule
+	pattern *regexp.Regexp
+	number  float64
+}
+
+// Compile validates rules and prepares them for evaluation
+func Compile(r volley.ConnectionRules) (*Rule, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	rule := &Rule{transform: r.Transform}
+	for _, f := range r.Filters {
+		compiled := filter{FilterRule: f}
+		switch f.Op {
+		case volley.OpMatches:
+			compiled.pattern = regexp.MustCompile(f.Value)
+		case volley.OpGreater, volley.OpLess:
+			compiled.number, _ = strconv.ParseFloat(f.Value, 64)
+		}
+		rule.filters = append(rule.filters, compiled)
+	}
+	if r.Transform != nil && r.Transform.Type == volley.TransformTemplate {
+		tmpl, err := r.Transform.ParseTemplate()
+		if err != nil {
+			return nil, err
+		}
+		rule.template = tmpl
+	}
+	return rule, nil
+}
+
+// Result is the outcome of evaluating a Rule against one event
+type Result struct {
+	EventID string `json:"event_id"`
+	Matched bool   `json:"matched"`
+	// Reason names the first filter that did not match
+	Reason string `json:"reason,omitempty"`
+	// Body and ContentType are what a matching event is delivered with
+	Body        string `json:"body,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	// Error is set if the event could not be evaluated; it is then not matched
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the outcome of evaluating a Rule against a set of events
+type Report struct {
+	Total   int      `json:"total"`
+	Matched int      `json:"matched"`
+	Errors  int      `json:"errors"`
+	Results []Result `json:"results"`
+}
+
+// Test evaluates the rule against each event in order
+func (r *Rule) Test(events []volley.Event) *Report {
+	report := &Report{Total: len(events), Results: make([]Result, 0, len(events))}
+	for _, ev := range events {
+		res := r.Evaluate(ev)
+		if res.Matched {
+			report.Matched++
+		}
+		if res.Error != "" {
+			report.Errors++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// Evaluate filters an event and, if it matches, transforms its body
+func (r *Rule) Evaluate(ev volley.Event) Result {
+	res := Result{EventID: ev.EventID}
+	in := newInput(ev)
+
+	failed, err := r.match(in)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if failed != nil {
+		res.Reason = fmt.Sprintf("%s %s %s did not match", failed.Target, failed.Path, failed.Op)
+		return res
+	}
+
+	body, contentType, err := r.apply(in)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Matched, res.Body, res.ContentType = true, body, contentType
+	return res
+}
+
+// Match reports whether an event passes every filter
+func (r *Rule) Match(ev volley.Event) (bool, error) {
+	failed, err := r.match(newInput(ev))
+	return err == nil && failed == nil, err
+}
+
+// Transform returns the body and content type an event is delivered with. An
+// event is delivered unchanged if the rule has no transform.
+func (r *Rule) Transform(ev volley.Event) (string, string, error) {
+	return r.apply(newInput(ev))
+}
+
+// match returns the first filter the event fails, or nil if it passes them all
+func (r *Rule) match(in *input) (*filter, error) {
+	for i := range r.filters {
+		f := &r.filters[i]
+		value, ok, err := in.lookup(f.Target, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !f.matches(value, ok) {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *filter) matches(value string, ok bool) bool {
+	switch f.Op {
+	case volley.OpExists:
+		return ok
+	case volley.OpNotExists:
+		return !ok
+	case volley.OpNotEquals:
+		return !ok || value != f.Value
+	}
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case volley.OpEquals:
+		return value == f.Value
+	case volley.OpIn:
+		for _, v := range f.Values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	case volley.OpContains:
+		return strings.Contains(value, f.Value)
+	case volley.OpPrefix:
+		return strings.HasPrefix(value, f.Value)
+	case volley.OpMatches:
+		return f.pattern.MatchString(value)
+	case volley.OpGreater, volley.OpLess:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		if f.Op == volley.OpGreater {
+			return n > f.number
+		}
+		return n < f.number
+	}
+	return false
+}
+
+// apply transforms the event's body
+func (r *Rule) apply(in *input) (string, string, error) {
+	if r.transform == nil {
+		return in.ev.RawBody, in.header("Content-Type"), nil
+	}
+
+	if r.template != nil {
+		body, err := in.json()
+		if err != nil {
+			// Templates may render bodies that are not JSON through .RawBody
+			body = nil
+		}
+		var buf bytes.Buffer
+		err = r.template.Execute(&buf, map[string]interface{}{
+			"Body":    body,
+			"RawBody": in.ev.RawBody,
+			"Headers": in.headers(),
+			"EventID": in.ev.EventID,
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("template transform: %w", err)
+		}
+		contentType := r.transform.ContentType
+		if contentType == "" {
+			contentType = in.header("Content-Type")
+		}
+		return buf.String(), contentType, nil
+	}
+
+	out := make(map[string]interface{})
+	for _, m := range r.transform.Mappings {
+		var value interface{}
+		if m.Target == volley.TargetHeader {
+			v, ok := in.headerValue(m.Path)
+			if !ok {
+				continue
+			}
+			value = v
+		} else {
+			body, err := in.json()
+			if err != nil {
+				return "", "", err
+			}
+			v, ok := lookupPath(body, m.Path)
+			if !ok {
+				continue
+			}
+			value = v
+		}
+		setPath(out, m.To, value)
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", "", fmt.Errorf("map transform: %w", err)
+	}
+	return string(b), "application/json", nil
+}
@@ -0,0 +1,154 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/rules"
+)
+
+func event(id, body string) volley.Event {
+	return volley.Event{
+		EventID: id,
+		RawBody: body,
+		Headers: map[string]interface{}{"Content-Type": "application/json", "X-Event-Type": []interface{}{"charge.succeeded"}},
+	}
+}
+
+func TestFilters(t *testing.T) {
+	ev := event("evt_1", `{"type":"charge.succeeded","livemode":false,"data":{"amount":250,"items":[{"id":"it_1"}]}}`)
+	tests := []struct {
+		filter volley.FilterRule
+		want   bool
+	}{
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpEquals, Value: "charge.succeeded"}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpNotEquals, Value: "charge.succeeded"}, false},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "missing", Op: volley.OpNotEquals, Value: "x"}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpIn, Values: []string{"charge.refunded", "charge.succeeded"}}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpContains, Value: "succ"}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpPrefix, Value: "customer."}, false},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "type", Op: volley.OpMatches, Value: `^charge\.(succeeded|failed)$`}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "data.amount", Op: volley.OpGreater, Value: "100"}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "data.amount", Op: volley.OpLess, Value: "100"}, false},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "livemode", Op: volley.OpEquals, Value: "false"}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "data.items.0.id", Op: volley.OpExists}, true},
+		{volley.FilterRule{Target: volley.TargetBody, Path: "data.items.1.id", Op: volley.OpNotExists}, true},
+		{volley.FilterRule{Target: volley.TargetHeader, Path: "x-event-type", Op: volley.OpEquals, Value: "charge.succeeded"}, true},
+		{volley.FilterRule{Target: volley.TargetHeader, Path: "X-Missing", Op: volley.OpExists}, false},
+	}
+	for _, tt := range tests {
+		rule, err := rules.Compile(volley.ConnectionRules{Filters: []volley.FilterRule{tt.filter}})
+		if err != nil {
+			t.Fatalf("Compile %+v failed: %v", tt.filter, err)
+		}
+		if got, err := rule.Match(ev); err != nil || got != tt.want {
+			t.Errorf("%s %s %s: expected %v, got %v (%v)", tt.filter.Target, tt.filter.Path, tt.filter.Op, tt.want, got, err)
+		}
+	}
+}
+
+func TestEvaluateInvalidBody(t *testing.T) {
+	rule, err := rules.Compile(volley.ConnectionRules{Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpExists}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := rule.Evaluate(event("evt_1", "not json"))
+	if res.Matched || res.Error == "" {
+		t.Errorf("Expected a body that is not JSON to be an error, got %+v", res)
+	}
+
+	// Header filters do not need the body
+	rule, err = rules.Compile(volley.ConnectionRules{Filters: []volley.FilterRule{{Target: volley.TargetHeader, Path: "X-Event-Type", Op: volley.OpExists}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := rule.Evaluate(event("evt_1", "not json")); !res.Matched || res.Body != "not json" {
+		t.Errorf("Expected the event to match unchanged, got %+v", res)
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	ev := event("evt_1", `{"type":"charge.succeeded","data":{"id":"ch_1","amount":250}}`)
+
+	mapped, err := rules.Compile(volley.ConnectionRules{Transform: &volley.Transform{
+		Type: volley.TransformMap,
+		Mappings: []volley.FieldMapping{
+			{Target: volley.TargetBody, Path: "data.id", To: "charge.id"},
+			{Target: volley.TargetBody, Path: "data.amount", To: "charge.amount"},
+			{Target: volley.TargetBody, Path: "data.currency", To: "charge.currency"},
+			{Target: volley.TargetHeader, Path: "x-event-type", To: "type"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, contentType, err := mapped.Transform(ev)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if want := `{"charge":{"amount":250,"id":"ch_1"},"type":"charge.succeeded"}`; body != want || contentType != "application/json" {
+		t.Errorf("Expected %s, got %s (%s)", want, body, contentType)
+	}
+
+	templated, err := rules.Compile(volley.ConnectionRules{Transform: &volley.Transform{
+		Type:        volley.TransformTemplate,
+		Template:    `{"text": {{ json .Body.type }}, "id": "{{ .EventID }}"}`,
+		ContentType: "application/vnd.chat+json",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, contentType, err = templated.Transform(ev)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if want := `{"text": "charge.succeeded", "id": "evt_1"}`; body != want || contentType != "application/vnd.chat+json" {
+		t.Errorf("Expected %s, got %s (%s)", want, body, contentType)
+	}
+}
+
+func TestReport(t *testing.T) {
+	rule, err := rules.Compile(volley.ConnectionRules{Filters: []volley.FilterRule{{Target: volley.TargetBody, Path: "type", Op: volley.OpPrefix, Value: "charge."}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := rule.Test([]volley.Event{
+		event("evt_1", `{"type":"charge.succeeded"}`),
+		event("evt_2", `{"type":"customer.created"}`),
+		event("evt_3", `<xml/>`),
+	})
+	if report.Total != 3 || report.Matched != 1 || report.Errors != 1 {
+		t.Fatalf("Expected 1 of 3 events to match with 1 error, got %+v", report)
+	}
+	if reason := report.Results[1].Reason; reason != "body type prefix did not match" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+}
+
+func TestLoadEvents(t *testing.T) {
+	inputs := map[string]string{
+		"list response": `{"requests":[{"event_id":"evt_1","raw_body":"{}"},{"event_id":"evt_2","raw_body":"{}"}],"total":2}`,
+		"yaml list": `- event_id: evt_1
+  raw_body: "{}"
+- event_id: evt_2
+  raw_body: "{}"
+`,
+	}
+	for name, data := range inputs {
+		events, err := rules.LoadEvents([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: LoadEvents failed: %v", name, err)
+		}
+		if len(events) != 2 || events[0].EventID != "evt_1" || events[1].RawBody != "{}" {
+			t.Errorf("%s: unexpected events %+v", name, events)
+		}
+	}
+
+	events, err := rules.LoadEvents([]byte(`{"event_id":"evt_1","headers":{"X-Id":"1"}}`))
+	if err != nil || len(events) != 1 || events[0].Headers["X-Id"] != "1" {
+		t.Errorf("Expected a single event, got %+v (%v)", events, err)
+	}
+	if _, err := rules.LoadEvents([]byte(`"evt_1"`)); err == nil {
+		t.Error("Expected an error for a string")
+	}
+}
@@ -0,0 +1,103 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func TestServices(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	org, err := client.Organizations.Current()
+	if err != nil {
+		t.Fatalf("Organizations.Current failed: %v", err)
+	}
+	other, err := client.Organizations.Create(volley.CreateOrganizationRequest{Name: "Other"})
+	if err != nil {
+		t.Fatalf("Organizations.Create failed: %v", err)
+	}
+	if got, err := client.Organizations.Get(other.ID); err != nil || got.ID != other.ID {
+		t.Errorf("Expected Organizations.Get to return organization %d, got %v (%v)", other.ID, got, err)
+	}
+	if client.OrganizationID() != nil {
+		t.Error("Expected Organizations.Get to leave the client's organization unset")
+	}
+	if current, _ := client.Organizations.Current(); current.ID != org.ID {
+		t.Errorf("Expected the current organization %d, got %d", org.ID, current.ID)
+	}
+
+	project, err := client.Projects.Default()
+	if err != nil {
+		t.Fatalf("Projects.Default failed: %v", err)
+	}
+
+	src, err := client.Sources.Create(project.ID, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	if got, err := client.Sources.Get(src.ID); err != nil || got.Slug != src.Slug {
+		t.Errorf("Expected Sources.Get to return %q, got %v (%v)", src.Slug, got, err)
+	}
+	dest, err := client.Destinations.Create(project.ID, volley.CreateDestinationRequest{Name: "api", URL: "https://example.com/hooks"})
+	if err != nil {
+		t.Fatalf("Destinations.Create failed: %v", err)
+	}
+	conn, err := client.Connections.Create(project.ID, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID})
+	if err != nil {
+		t.Fatalf("Connections.Create failed: %v", err)
+	}
+	if found, err := client.Connections.Find(project.ID, src.ID, dest.ID); err != nil || found.ID != conn.ID {
+		t.Errorf("Expected Connections.Find to return connection %d, got %v (%v)", conn.ID, found, err)
+	}
+
+	if _, err := client.Ingestion.Send(src.IngestionID, map[string]string{"type": "charge.succeeded"}); err != nil {
+		t.Fatalf("Ingestion.Send failed: %v", err)
+	}
+	events, err := client.Events.List(project.ID, nil)
+	if err != nil || events.Total != 1 {
+		t.Fatalf("Expected 1 event, got %v (%v)", events, err)
+	}
+	if event, err := client.Events.Get(events.Requests[0].ID); err != nil || event.SourceID != src.ID {
+		t.Errorf("Expected Events.Get to return the event from source %d, got %v (%v)", src.ID, event, err)
+	}
+
+	if err := client.Connections.Delete(conn.ID); err != nil {
+		t.Fatalf("Connections.Delete failed: %v", err)
+	}
+	if _, err := client.Connections.Find(project.ID, src.ID, dest.ID); !errors.Is(err, volley.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Connections.Delete, got %v", err)
+	}
+}
+
+func TestDeprecatedWrappers(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	src, err := client.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		t.Fatalf("CreateSource failed: %v", err)
+	}
+	sources, err := client.Sources.List(1)
+	if err != nil || len(sources) != 1 || sources[0].ID != src.ID {
+		t.Errorf("Expected the wrapper's source to be listed, got %v (%v)", sources, err)
+	}
+	if client.IngestionURL(src.IngestionID) != client.Ingestion.URL(src.IngestionID) {
+		t.Error("Expected IngestionURL to match Ingestion.URL")
+	}
+	if org, err := client.GetOrganization(nil); err != nil || org.ID != 1 {
+		t.Errorf("Expected GetOrganization(nil) to return the current organization, got %v (%v)", org, err)
+	}
+}
@@ -0,0 +1,290 @@
+package volley_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// postWebhook sends body to a source's ingestion URL and returns the status code
+func postWebhook(t *testing.T, client *volley.Client, src *volley.Source, body []byte, header http.Header) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, client.Ingestion.URL(src.IngestionID), bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func signed(secret string, body []byte) http.Header {
+	return http.Header{volley.SignatureHeader: {volley.SignWebhook(secret, body)}}
+}
+
+func TestSourceSecretRotation(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	body := []byte(`{"type": "charge.succeeded"}`)
+
+	if _, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "nosecret", VerifySignature: true}); err == nil {
+		t.Error("Expected verifying signatures without a secret to be rejected")
+	}
+	src, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "stripe", VerifySignature: true, WebhookSecret: "first"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	if !src.VerifySignature || !src.WebhookSecretSet {
+		t.Errorf("Expected a verified source with a secret, got %+v", src)
+	}
+	if code := postWebhook(t, client, src, body, nil); code != http.StatusUnauthorized {
+		t.Errorf("Expected an unsigned webhook to be rejected, got %d", code)
+	}
+	if code := postWebhook(t, client, src, body, signed("first", body)); code != http.StatusAccepted {
+		t.Errorf("Expected a signed webhook to be accepted, got %d", code)
+	}
+
+	// During the grace period both secrets are accepted
+	rotated, err := client.Sources.RotateSecret(src.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("RotateSecret failed: %v", err)
+	}
+	if rotated.WebhookSecret == "" || rotated.WebhookSecret == "first" || rotated.PreviousSecretExpiresAt == nil {
+		t.Fatalf("Expected a new secret with a grace period, got %+v", rotated)
+	}
+	for _, secret := range []string{"first", string(rotated.WebhookSecret)} {
+		if code := postWebhook(t, client, src, body, signed(secret, body)); code != http.StatusAccepted {
+			t.Errorf("Expected a webhook signed with %q to be accepted during the grace period, got %d", secret, code)
+		}
+	}
+
+	// Without a grace period the old secret stops working at once
+	again, err := client.RotateSourceSecret(src.ID, 0)
+	if err != nil {
+		t.Fatalf("RotateSecret failed: %v", err)
+	}
+	if again.PreviousSecretExpiresAt != nil {
+		t.Errorf("Expected no grace period, got %v", again.PreviousSecretExpiresAt)
+	}
+	if code := postWebhook(t, client, src, body, signed(string(rotated.WebhookSecret), body)); code != http.StatusUnauthorized {
+		t.Errorf("Expected the replaced secret to be rejected, got %d", code)
+	}
+	if code := postWebhook(t, client, src, body, signed(string(again.WebhookSecret), body)); code != http.StatusAccepted {
+		t.Errorf("Expected the new secret to be accepted, got %d", code)
+	}
+}
+
+func TestSourceAuthCredentials(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	body := []byte(`{}`)
+
+	src, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "github"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+
+	creds, err := client.Sources.SetBasicAuth(src.ID, "hooks", "")
+	if err != nil {
+		t.Fatalf("SetBasicAuth failed: %v", err)
+	}
+	if creds.Password == "" || creds.Source.AuthType != "basic" || creds.Source.AuthUsername != "hooks" {
+		t.Fatalf("Expected a generated password for basic auth, got %+v", creds)
+	}
+	if code := postWebhook(t, client, src, body, nil); code != http.StatusUnauthorized {
+		t.Errorf("Expected a webhook without credentials to be rejected, got %d", code)
+	}
+	req, _ := http.NewRequest(http.MethodPost, "", nil)
+	req.SetBasicAuth("hooks", string(creds.Password))
+	if code := postWebhook(t, client, src, body, req.Header); code != http.StatusAccepted {
+		t.Errorf("Expected basic credentials to be accepted, got %d", code)
+	}
+
+	creds, err = client.SetSourceAPIKey(src.ID, "X-Api-Key", "supplied-key")
+	if err != nil {
+		t.Fatalf("SetAPIKey failed: %v", err)
+	}
+	if creds.Key != "" || creds.Password != "" || creds.Source.AuthKeyName != "X-Api-Key" || creds.Source.AuthUsername != "" {
+		t.Errorf("Expected only the key name to be returned for a supplied key, got %+v", creds)
+	}
+	if code := postWebhook(t, client, src, body, http.Header{"X-Api-Key": {"supplied-key"}}); code != http.StatusAccepted {
+		t.Errorf("Expected the API key to be accepted, got %d", code)
+	}
+	if code := postWebhook(t, client, src, body, req.Header); code != http.StatusUnauthorized {
+		t.Errorf("Expected the replaced basic credentials to be rejected, got %d", code)
+	}
+}
+
+func TestSourceCredentialsNotForwarded(t *testing.T) {
+	received := make(chan receivedRequest, 10)
+	target := httptest.NewServer(receiver(received))
+	defer target.Close()
+
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	src, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "github"})
+	if err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+	dest, err := client.Destinations.Create(1, volley.CreateDestinationRequest{Name: "api", URL: target.URL})
+	if err != nil {
+		t.Fatalf("Destinations.Create failed: %v", err)
+	}
+	if _, err := client.Connections.Create(1, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID}); err != nil {
+		t.Fatalf("Connections.Create failed: %v", err)
+	}
+
+	if _, err := client.Sources.SetBasicAuth(src.ID, "hooks", "hunter2"); err != nil {
+		t.Fatalf("SetBasicAuth failed: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, "", nil)
+	req.SetBasicAuth("hooks", "hunter2")
+	req.Header.Set("X-Tenant", "acme")
+	if code := postWebhook(t, client, src, []byte(`{}`), req.Header); code != http.StatusAccepted {
+		t.Fatalf("Expected the webhook to be accepted, got %d", code)
+	}
+	if err := emu.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle failed: %v", err)
+	}
+	if got := <-received; got.header.Get("Authorization") != "" || got.header.Get("X-Tenant") != "acme" {
+		t.Errorf("Expected the basic credentials to be stripped, got %v", got.header)
+	}
+
+	if _, err := client.Sources.SetAPIKey(src.ID, "x-api-key", "supplied-key"); err != nil {
+		t.Fatalf("SetAPIKey failed: %v", err)
+	}
+	if code := postWebhook(t, client, src, []byte(`{}`), http.Header{"X-Api-Key": {"supplied-key"}}); code != http.StatusAccepted {
+		t.Fatalf("Expected the webhook to be accepted, got %d", code)
+	}
+	if err := emu.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle failed: %v", err)
+	}
+	if got := <-received; got.header.Get("X-Api-Key") != "" {
+		t.Errorf("Expected the API key to be stripped, got %v", got.header)
+	}
+}
+
+func TestApplyTopologySecrets(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	t.Setenv("VOLLEY_SOURCE_STRIPE_WEBHOOK_SECRET", "from-env")
+
+	spec, err := volley.LoadTopology([]byte(`sources:
+  - slug: stripe
+    eps: 10
+    verify_signature: true
+    webhook_secret: ${VOLLEY_SOURCE_STRIPE_WEBHOOK_SECRET}
+`))
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+	plan, err := client.PlanTopology(1, spec, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	result, err := client.ApplyTopology(plan)
+	if err != nil {
+		t.Fatalf("ApplyTopology failed: %v", err)
+	}
+	src := result.Sources["stripe"]
+	if !src.VerifySignature || !src.WebhookSecretSet {
+		t.Fatalf("Expected the secret to be applied, got %+v", src)
+	}
+	body := []byte(`{}`)
+	if code := postWebhook(t, client, &src, body, signed("from-env", body)); code != http.StatusAccepted {
+		t.Errorf("Expected the secret from the environment to verify webhooks, got %d", code)
+	}
+
+	// Exporting and planning again shows no changes
+	exported, err := client.ExportProject(1)
+	if err != nil {
+		t.Fatalf("ExportProject failed: %v", err)
+	}
+	if plan, err := client.PlanTopology(1, exported, nil); err != nil || !plan.Empty() {
+		t.Errorf("Expected an exported project to plan no changes, got %v (%v)", plan, err)
+	}
+}
+
+func TestApplyTopologyKeepsSignatureVerification(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	if _, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "stripe", EPS: 10, VerifySignature: true, WebhookSecret: "whsec"}); err != nil {
+		t.Fatalf("Sources.Create failed: %v", err)
+	}
+
+	// A spec that does not mention verify_signature leaves it alone
+	spec, err := volley.LoadTopology([]byte("sources:\n  - slug: stripe\n    eps: 20\n"))
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+	plan, err := client.PlanTopology(1, spec, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if len(plan.Changes) != 1 || len(plan.Changes[0].Fields) != 1 || plan.Changes[0].Fields[0].Field != "eps" {
+		t.Fatalf("Expected only eps to change, got %v", plan)
+	}
+	result, err := client.ApplyTopology(plan)
+	if err != nil {
+		t.Fatalf("ApplyTopology failed: %v", err)
+	}
+	if src := result.Sources["stripe"]; !src.VerifySignature || src.EPS != 20 {
+		t.Errorf("Expected signature verification to stay on, got %+v", src)
+	}
+	if report, err := client.Drift(1, spec); err != nil || report.HasDrift() {
+		t.Errorf("Expected no drift, got %+v (%v)", report, err)
+	}
+}
+
+func TestSourceSecretsRedacted(t *testing.T) {
+	create := volley.CreateSourceRequest{Name: "stripe", WebhookSecret: "whsec", AuthType: "basic", AuthUsername: "hooks", AuthPassword: "hunter2"}
+	update := volley.UpdateSourceRequest{AuthKeyName: "X-Api-Key", AuthKey: "hunter2"}
+	rotated := volley.SourceSecret{WebhookSecret: "whsec_rotated"}
+	creds := volley.SourceCredentials{Password: "hunter2"}
+
+	var logged bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logged, nil)).Info("updating sources", "create", create, "update", update, "rotated", rotated, "creds", creds)
+	for _, out := range []string{fmt.Sprintf("%+v", create), fmt.Sprintf("%+v", update), fmt.Sprintf("%+v", rotated), fmt.Sprintf("%v", creds), logged.String()} {
+		if strings.Contains(out, "whsec") || strings.Contains(out, "hunter2") || !strings.Contains(out, "[REDACTED]") {
+			t.Errorf("Expected secrets to be redacted, got %s", out)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package volley
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
 
-// ListSources lists all sources in a project
-func (c *Client) ListSources(projectID uint64) ([]Source, error) {
+// SourcesService manages webhook sources
+type SourcesService service
+
+// List lists all sources in a project
+func (s *SourcesService) List(projectID uint64) ([]Source, error) {
 	path := fmt.Sprintf("/api/projects/%d/sources", projectID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -13,7 +20,7 @@ func (c *Client) ListSources(projectID uint64) ([]Source, error) {
 	var result struct {
 		Sources []Source `json:"sources"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -25,12 +32,30 @@ type CreateSourceRequest struct {
 	Name     string `json:"name"`
 	EPS      int    `json:"eps"`
 	AuthType string `json:"auth_type"` // "none", "basic", "api_key"
+
+	// VerifySignature rejects webhooks not signed with the webhook secret
+	// (see SignWebhook)
+	VerifySignature bool   `json:"verify_signature,omitempty"`
+	WebhookSecret   Secret `json:"webhook_secret,omitempty"`
+	// Credentials for basic auth
+	AuthUsername string `json:"auth_username,omitempty"`
+	AuthPassword Secret `json:"auth_password,omitempty"`
+	// Credentials for API key auth: the header named AuthKeyName must carry AuthKey
+	AuthKeyName string `json:"auth_key_name,omitempty"`
+	AuthKey     Secret `json:"auth_key,omitempty"`
 }
 
-// CreateSource creates a new source
-func (c *Client) CreateSource(projectID uint64, req CreateSourceRequest) (*Source, error) {
+// LogValue redacts the request's secrets in log/slog output
+func (r CreateSourceRequest) LogValue() slog.Value {
+	type plain CreateSourceRequest
+	r.WebhookSecret, r.AuthPassword, r.AuthKey = r.WebhookSecret.redacted(), r.AuthPassword.redacted(), r.AuthKey.redacted()
+	return slog.AnyValue(plain(r))
+}
+
+// Create creates a new source
+func (s *SourcesService) Create(projectID uint64, req CreateSourceRequest) (*Source, error) {
 	path := fmt.Sprintf("/api/projects/%d/sources", projectID)
-	resp, err := c.doRequest("POST", path, req, nil)
+	resp, err := s.client.doRequest("POST", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,17 +63,17 @@ func (c *Client) CreateSource(projectID uint64, req CreateSourceRequest) (*Sourc
 	var result struct {
 		Source Source `json:"source"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Source, nil
 }
 
-// GetSource gets details of a specific source
-func (c *Client) GetSource(sourceID uint64) (*Source, error) {
+// Get gets details of a specific source
+func (s *SourcesService) Get(sourceID uint64) (*Source, error) {
 	path := fmt.Sprintf("/api/sources/%d", sourceID)
-	resp, err := c.doRequest("GET", path, nil, nil)
+	resp, err := s.client.doRequest("GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +81,7 @@ func (c *Client) GetSource(sourceID uint64) (*Source, error) {
 	var result struct {
 		Source Source `json:"source"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -69,12 +94,26 @@ type UpdateSourceRequest struct {
 	EPS      *int   `json:"eps,omitempty"`
 	AuthType string `json:"auth_type,omitempty"`
 	Status   string `json:"status,omitempty"`
+
+	VerifySignature *bool  `json:"verify_signature,omitempty"`
+	WebhookSecret   Secret `json:"webhook_secret,omitempty"`
+	AuthUsername    string `json:"auth_username,omitempty"`
+	AuthPassword    Secret `json:"auth_password,omitempty"`
+	AuthKeyName     string `json:"auth_key_name,omitempty"`
+	AuthKey         Secret `json:"auth_key,omitempty"`
 }
 
-// UpdateSource updates a source
-func (c *Client) UpdateSource(sourceID uint64, req UpdateSourceRequest) (*Source, error) {
+// LogValue redacts the request's secrets in log/slog output
+func (r UpdateSourceRequest) LogValue() slog.Value {
+	type plain UpdateSourceRequest
+	r.WebhookSecret, r.AuthPassword, r.AuthKey = r.WebhookSecret.redacted(), r.AuthPassword.redacted(), r.AuthKey.redacted()
+	return slog.AnyValue(plain(r))
+}
+
+// Update updates a source
+func (s *SourcesService) Update(sourceID uint64, req UpdateSourceRequest) (*Source, error) {
 	path := fmt.Sprintf("/api/sources/%d", sourceID)
-	resp, err := c.doRequest("PUT", path, req, nil)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,21 +121,108 @@ func (c *Client) UpdateSource(sourceID uint64, req UpdateSourceRequest) (*Source
 	var result struct {
 		Source Source `json:"source"`
 	}
-	if err := c.parseResponse(resp, &result); err != nil {
+	if err := s.client.parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Source, nil
 }
 
-// DeleteSource deletes a source
-func (c *Client) DeleteSource(sourceID uint64) error {
+// Delete deletes a source
+func (s *SourcesService) Delete(sourceID uint64) error {
 	path := fmt.Sprintf("/api/sources/%d", sourceID)
-	resp, err := c.doRequest("DELETE", path, nil, nil)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	return c.parseResponse(resp, nil)
+	return s.client.parseResponse(resp, nil)
+}
+
+// SourceSecret is a source's new webhook secret, returned only by RotateSecret
+type SourceSecret struct {
+	Source        Source `json:"source"`
+	WebhookSecret Secret `json:"webhook_secret"`
+	// PreviousSecretExpiresAt is when the replaced secret stops being
+	// accepted, or nil if it was revoked immediately
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+}
+
+// LogValue redacts the webhook secret in log/slog output
+func (s SourceSecret) LogValue() slog.Value {
+	type plain SourceSecret
+	s.WebhookSecret = s.WebhookSecret.redacted()
+	return slog.AnyValue(plain(s))
+}
+
+// RotateSecret generates a new webhook secret for a source. During
+// gracePeriod, webhooks signed with either the old or the new secret are
+// accepted, so senders can switch over without dropping events; a zero
+// gracePeriod revokes the old secret immediately.
+func (s *SourcesService) RotateSecret(sourceID uint64, gracePeriod time.Duration) (*SourceSecret, error) {
+	path := fmt.Sprintf("/api/sources/%d/rotate-secret", sourceID)
+	req := struct {
+		GracePeriodSeconds int64 `json:"grace_period_seconds"`
+	}{int64(gracePeriod / time.Second)}
+	resp, err := s.client.doRequest("POST", path, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SourceSecret
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SourceCredentials is a source with its new auth credentials. A password or
+// key generated by the API is returned only once, here.
+type SourceCredentials struct {
+	Source   Source `json:"source"`
+	Password Secret `json:"password,omitempty"`
+	Key      Secret `json:"key,omitempty"`
+}
+
+// LogValue redacts the generated credentials in log/slog output
+func (c SourceCredentials) LogValue() slog.Value {
+	type plain SourceCredentials
+	c.Password, c.Key = c.Password.redacted(), c.Key.redacted()
+	return slog.AnyValue(plain(c))
 }
 
+// SetBasicAuth switches a source to basic auth. If password is empty, one is
+// generated and returned.
+func (s *SourcesService) SetBasicAuth(sourceID uint64, username, password string) (*SourceCredentials, error) {
+	return s.setAuth(sourceID, sourceAuthRequest{AuthType: "basic", Username: username, Password: password})
+}
+
+// SetAPIKey switches a source to API key auth: webhooks must carry the key in
+// the header named keyName. If key is empty, one is generated and returned.
+func (s *SourcesService) SetAPIKey(sourceID uint64, keyName, key string) (*SourceCredentials, error) {
+	return s.setAuth(sourceID, sourceAuthRequest{AuthType: "api_key", KeyName: keyName, Key: key})
+}
+
+type sourceAuthRequest struct {
+	AuthType string `json:"auth_type"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	KeyName  string `json:"key_name,omitempty"`
+	Key      string `json:"key,omitempty"`
+}
+
+func (s *SourcesService) setAuth(sourceID uint64, req sourceAuthRequest) (*SourceCredentials, error) {
+	path := fmt.Sprintf("/api/sources/%d/auth", sourceID)
+	resp, err := s.client.doRequest("PUT", path, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SourceCredentials
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,93 @@
+package volley
+
+import (
+	"fmt"
+	"time"
+)
+
+// TokensService manages the caller's API tokens. Rotating a token without
+// downtime is a matter of creating a new one, rolling it out (for example by
+// rewriting the file behind a FileToken) and revoking the old one.
+type TokensService service
+
+// List lists the caller's API tokens
+func (s *TokensService) List() ([]APIToken, error) {
+	resp, err := s.client.doRequest("GET", "/api/tokens", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Tokens []APIToken `json:"tokens"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Tokens, nil
+}
+
+// CreateTokenRequest represents the request to create an API token
+type CreateTokenRequest struct {
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+	// ExpiresAt is optional; tokens without it do not expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewAPIToken is a newly created API token with its secret
+type NewAPIToken struct {
+	APIToken
+	// Token is the secret to authenticate with. It cannot be retrieved again.
+	Token string `json:"token"`
+}
+
+// Create creates an API token
+func (s *TokensService) Create(req CreateTokenRequest) (*NewAPIToken, error) {
+	resp, err := s.client.doRequest("POST", "/api/tokens", req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Token NewAPIToken `json:"token"`
+	}
+	if err := s.client.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Token, nil
+}
+
+// Revoke revokes an API token. Requests made with it fail from then on.
+func (s *TokensService) Revoke(tokenID uint64) error {
+	path := fmt.Sprintf("/api/tokens/%d", tokenID)
+	resp, err := s.client.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.parseResponse(resp, nil)
+}
+
+// TokenInfo identifies the token a request was made with and its owner
+type TokenInfo struct {
+	Token  APIToken `json:"token"`
+	UserID uint64   `json:"user_id"`
+	Email  string   `json:"email"`
+}
+
+// Current introspects the token the client authenticates with
+func (s *TokensService) Current() (*TokenInfo, error) {
+	resp, err := s.client.doRequest("GET", "/api/tokens/current", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info TokenInfo
+	if err := s.client.parseResponse(resp, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,107 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+func TestTokenRotation(t *testing.T) {
+	emu := emulator.New(&emulator.Options{Token: "bootstrap-token"})
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	admin := volley.NewClient("bootstrap-token", volley.WithBaseURL(server.URL))
+
+	old, err := admin.Tokens.Create(volley.CreateTokenRequest{Name: "deploy", Scopes: []volley.Scope{volley.ScopeRead, volley.ScopeWrite}})
+	if err != nil {
+		t.Fatalf("Tokens.Create failed: %v", err)
+	}
+	if old.Token == "" || !strings.HasPrefix(old.Token, old.Prefix) || old.ExpiresAt != nil {
+		t.Errorf("Expected a non-expiring token with its secret, got %+v", old)
+	}
+
+	// The job authenticates through a token file
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte(old.Token), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	job := volley.NewClient("", volley.WithBaseURL(server.URL), volley.WithTokenProvider(volley.NewFileToken(tokenFile)))
+
+	info, err := job.Tokens.Current()
+	if err != nil {
+		t.Fatalf("Tokens.Current failed: %v", err)
+	}
+	if info.Token.ID != old.ID || !info.Token.HasScope(volley.ScopeWrite) || info.Token.HasScope(volley.ScopeAdmin) || info.Email == "" {
+		t.Errorf("Expected the deploy token's identity and scopes, got %+v", info)
+	}
+
+	// Mint a replacement, roll it out, then revoke the old token
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	replacement, err := admin.Tokens.Create(volley.CreateTokenRequest{Name: "deploy", Scopes: old.Scopes, ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("Tokens.Create failed: %v", err)
+	}
+	if replacement.ExpiresAt == nil || !replacement.ExpiresAt.Equal(expires) {
+		t.Errorf("Expected the replacement to expire at %v, got %v", expires, replacement.ExpiresAt)
+	}
+	time.Sleep(10 * time.Millisecond) // let the file's modification time change
+	if err := os.WriteFile(tokenFile, []byte(replacement.Token), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := admin.Tokens.Revoke(old.ID); err != nil {
+		t.Fatalf("Tokens.Revoke failed: %v", err)
+	}
+
+	if info, err := job.Tokens.Current(); err != nil || info.Token.ID != replacement.ID {
+		t.Errorf("Expected the job to use the replacement, got %v (%v)", info, err)
+	}
+	tokens, err := admin.Tokens.List()
+	if err != nil || len(tokens) != 1 || tokens[0].ID != replacement.ID || tokens[0].LastUsedAt == nil {
+		t.Errorf("Expected only the used replacement to be listed, got %+v (%v)", tokens, err)
+	}
+
+	revoked := volley.NewClient(old.Token, volley.WithBaseURL(server.URL))
+	var apiErr *volley.APIError
+	if _, err := revoked.Projects.List(); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("Expected the revoked token to be rejected, got %v", err)
+	}
+}
+
+func TestTokenScopes(t *testing.T) {
+	emu := emulator.New(nil)
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	defer emu.Close()
+
+	admin := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+	readOnly, err := admin.Tokens.Create(volley.CreateTokenRequest{Name: "dashboards", Scopes: []volley.Scope{volley.ScopeRead}})
+	if err != nil {
+		t.Fatalf("Tokens.Create failed: %v", err)
+	}
+
+	client := volley.NewClient(readOnly.Token, volley.WithBaseURL(server.URL))
+	if _, err := client.Sources.List(1); err != nil {
+		t.Errorf("Expected a read token to list sources, got %v", err)
+	}
+	var apiErr *volley.APIError
+	if _, err := client.Sources.Create(1, volley.CreateSourceRequest{Name: "stripe"}); !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected a read token to be forbidden from creating sources, got %v", err)
+	}
+	if _, err := client.Tokens.Create(volley.CreateTokenRequest{Name: "escalate", Scopes: []volley.Scope{volley.ScopeAdmin}}); !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected a read token to be forbidden from creating tokens, got %v", err)
+	}
+
+	if _, err := admin.Tokens.Create(volley.CreateTokenRequest{Name: "none"}); err == nil {
+		t.Error("Expected a token without scopes to be rejected")
+	}
+}
@@ -0,0 +1,710 @@
+package volley
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TopologySpec declaratively describes the sources, destinations and
+// connections of a project. Objects reference each other by name rather than ID:
+// sources by slug, destinations by name.
+type TopologySpec struct {
+	Project      string            `json:"project,omitempty" yaml:"project,omitempty"`
+	Sources      []SourceSpec      `json:"sources" yaml:"sources"`
+	Destinations []DestinationSpec `json:"destinations" yaml:"destinations"`
+	Connections  []ConnectionSpec  `json:"connections" yaml:"connections"`
+}
+
+// SourceSpec describes a source in a TopologySpec
+type SourceSpec struct {
+	Slug            string `json:"slug" yaml:"slug"`
+	EPS             int    `json:"eps" yaml:"eps"`
+	AuthType        string `json:"auth_type,omitempty" yaml:"auth_type,omitempty"` // "none", "basic", "api_key"
+	Status          string `json:"status,omitempty" yaml:"status,omitempty"`
+	VerifySignature *bool  `json:"verify_signature,omitempty" yaml:"verify_signature,omitempty"` // left unchanged if unset
+	AuthUsername    string `json:"auth_username,omitempty" yaml:"auth_username,omitempty"`
+	AuthKeyName     string `json:"auth_key_name,omitempty" yaml:"auth_key_name,omitempty"`
+
+	// Secret values are never read back from the API. ExportProject emits
+	// them as placeholders (see SecretPlaceholder), which ApplyTopology reads
+	// from the environment; secrets whose variable is unset are left unchanged.
+	// Since secrets cannot be compared, they are only sent along with other changes.
+	WebhookSecret string `json:"webhook_secret,omitempty" yaml:"webhook_secret,omitempty"`
+	AuthPassword  string `json:"auth_password,omitempty" yaml:"auth_password,omitempty"`
+	AuthKey       string `json:"auth_key,omitempty" yaml:"auth_key,omitempty"`
+}
+
+// DestinationSpec describes a destination in a TopologySpec
+type DestinationSpec struct {
+	Name           string            `json:"name" yaml:"name"`
+	URL            string            `json:"url" yaml:"url"`
+	EPS            int               `json:"eps" yaml:"eps"`
+	Status         string            `json:"status,omitempty" yaml:"status,omitempty"`
+	Method         string            `json:"method,omitempty" yaml:"method,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`                 // left unchanged if unset; empty removes them
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"` // left unchanged if unset
+	AuthType       string            `json:"auth_type,omitempty" yaml:"auth_type,omitempty"`             // "none", "bearer", "basic"
+	AuthUsername   string            `json:"auth_username,omitempty" yaml:"auth_username,omitempty"`
+
+	// Secrets are handled as in SourceSpec
+	AuthPassword string `json:"auth_password,omitempty" yaml:"auth_password,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty" yaml:"auth_token,omitempty"`
+	ClientCert   string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey    string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+}
+
+// ConnectionSpec describes a connection in a TopologySpec
+type ConnectionSpec struct {
+	Source      string         `json:"source" yaml:"source"`           // source slug
+	Destination string         `json:"destination" yaml:"destination"` // destination name
+	Status      string         `json:"status,omitempty" yaml:"status,omitempty"`
+	EPS         int            `json:"eps" yaml:"eps"`
+	MaxRetries  int            `json:"max_retries" yaml:"max_retries"`
+	Filters     []FilterRule   `json:"filters,omitempty" yaml:"filters,omitempty"`
+	Transform   *Transform     `json:"transform,omitempty" yaml:"transform,omitempty"`
+	Retry       *RetryStrategy `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// Key returns the name a connection is referenced by in plans and results
+func (s ConnectionSpec) Key() string {
+	return connectionKey(s.Source, s.Destination)
+}
+
+func connectionKey(source, destination string) string {
+	return source + " -> " + destination
+}
+
+// LoadTopology parses a TopologySpec from YAML or JSON
+func LoadTopology(data []byte) (*TopologySpec, error) {
+	var spec TopologySpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse topology: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// LoadTopologyFile reads and parses a TopologySpec from a YAML or JSON file
+func LoadTopologyFile(path string) (*TopologySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology: %w", err)
+	}
+	return LoadTopology(data)
+}
+
+// Validate checks that names are unique and that connections reference
+// sources and destinations declared in the spec
+func (s *TopologySpec) Validate() error {
+	sources := make(map[string]bool)
+	for _, src := range s.Sources {
+		if src.Slug == "" {
+			return fmt.Errorf("invalid topology: source slug is required")
+		}
+		if !validSlug(src.Slug) {
+			// The API derives slugs from names, so any other slug would be
+			// created under a different one and never match the spec again
+			return fmt.Errorf("invalid topology: source slug %q must be lowercase letters, digits and underscores separated by single dashes", src.Slug)
+		}
+		if sources[src.Slug] {
+			return fmt.Errorf("invalid topology: duplicate source %q", src.Slug)
+		}
+		sources[src.Slug] = true
+	}
+
+	destinations := make(map[string]bool)
+	for _, dest := range s.Destinations {
+		if dest.Name == "" {
+			return fmt.Errorf("invalid topology: destination name is required")
+		}
+		if dest.URL == "" {
+			return fmt.Errorf("invalid topology: destination %q has no url", dest.Name)
+		}
+		if destinations[dest.Name] {
+			return fmt.Errorf("invalid topology: duplicate destination %q", dest.Name)
+		}
+		destinations[dest.Name] = true
+	}
+
+	connections := make(map[string]bool)
+	for _, conn := range s.Connections {
+		if !sources[conn.Source] {
+			return fmt.Errorf("invalid topology: connection %q references unknown source", conn.Key())
+		}
+		if !destinations[conn.Destination] {
+			return fmt.Errorf("invalid topology: connection %q references unknown destination", conn.Key())
+		}
+		if connections[conn.Key()] {
+			return fmt.Errorf("invalid topology: duplicate connection %q", conn.Key())
+		}
+		if err := validateConnectionRules(conn.Filters, conn.Transform, conn.Retry); err != nil {
+			return fmt.Errorf("invalid topology: connection %q: %w", conn.Key(), err)
+		}
+		connections[conn.Key()] = true
+	}
+
+	return nil
+}
+
+// validSlug reports whether slug is in the canonical form the API gives
+// source slugs, e.g. "stripe-payments"
+func validSlug(slug string) bool {
+	dash := true // no leading dash
+	for _, r := range slug {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_':
+			dash = false
+		case r == '-' && !dash:
+			dash = true
+		default:
+			return false
+		}
+	}
+	return !dash
+}
+
+// ChangeAction is the action a plan takes on a resource
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// ResourceKind identifies the type of resource a change applies to
+type ResourceKind string
+
+const (
+	KindProject     ResourceKind = "project"
+	KindSource      ResourceKind = "source"
+	KindDestination ResourceKind = "destination"
+	KindConnection  ResourceKind = "connection"
+)
+
+// FieldChange describes a single field that differs between spec and live state
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Change is a single step of a TopologyPlan. Creates and updates carry the
+// spec they apply, which is not serialized, so only plans returned by
+// PlanTopology can be applied.
+type Change struct {
+	Action ChangeAction  `json:"action"`
+	Kind   ResourceKind  `json:"kind"`
+	Name   string        `json:"name"`
+	ID     uint64        `json:"id,omitempty"` // live ID for updates and deletes
+	Fields []FieldChange `json:"fields,omitempty"`
+
+	source      *SourceSpec
+	destination *DestinationSpec
+	connection  *ConnectionSpec
+}
+
+func (ch Change) String() string {
+	symbol := map[ChangeAction]string{ChangeCreate: "+", ChangeUpdate: "~", ChangeDelete: "-"}[ch.Action]
+	s := fmt.Sprintf("%s %s %s", symbol, ch.Kind, ch.Name)
+	if len(ch.Fields) > 0 {
+		parts := make([]string, len(ch.Fields))
+		for i, f := range ch.Fields {
+			parts[i] = fmt.Sprintf("%s: %v -> %v", f.Field, f.From, f.To)
+		}
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return s
+}
+
+// TopologyOptions configures planning
+type TopologyOptions struct {
+	// Prune deletes live objects that are not present in the spec
+	Prune bool
+}
+
+// TopologyPlan is the set of changes required to bring a project in line with a spec.
+// Changes are ordered so that dependencies are created before, and deleted after,
+// the objects that reference them.
+type TopologyPlan struct {
+	ProjectID uint64   `json:"project_id"`
+	Changes   []Change `json:"changes"`
+
+	sourceIDs      map[string]uint64
+	destinationIDs map[string]uint64
+}
+
+// Empty reports whether the plan has no changes
+func (p *TopologyPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *TopologyPlan) String() string {
+	if p.Empty() {
+		return "No changes. Project is up to date.\n"
+	}
+	var b strings.Builder
+	for _, ch := range p.Changes {
+		b.WriteString(ch.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// liveTopology is the live state of a project indexed by name
+type liveTopology struct {
+	sources      map[string]Source
+	destinations map[string]Destination
+	connections  map[string]Connection
+	// orphans are connections whose source or destination no longer exists,
+	// or duplicates of a connection already indexed
+	orphans []Connection
+
+	sourceSlugs      map[uint64]string
+	destinationNames map[uint64]string
+}
+
+// fetchLiveTopology reads the live sources, destinations and connections of a project
+func (c *Client) fetchLiveTopology(projectID uint64) (*liveTopology, error) {
+	sources, err := c.Sources.List(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sources: %w", err)
+	}
+	destinations, err := c.Destinations.List(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list destinations: %w", err)
+	}
+	connections, err := c.Connections.List(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	live := &liveTopology{
+		sources:          make(map[string]Source),
+		destinations:     make(map[string]Destination),
+		connections:      make(map[string]Connection),
+		sourceSlugs:      make(map[uint64]string),
+		destinationNames: make(map[uint64]string),
+	}
+	for _, s := range sources {
+		live.sources[s.Slug] = s
+		live.sourceSlugs[s.ID] = s.Slug
+	}
+	for _, d := range destinations {
+		live.destinations[d.Name] = d
+		live.destinationNames[d.ID] = d.Name
+	}
+	for _, conn := range connections {
+		slug, okSource := live.sourceSlugs[conn.SourceID]
+		name, okDest := live.destinationNames[conn.DestinationID]
+		key := connectionKey(slug, name)
+		if _, dup := live.connections[key]; !okSource || !okDest || dup {
+			live.orphans = append(live.orphans, conn)
+			continue
+		}
+		live.connections[key] = conn
+	}
+
+	return live, nil
+}
+
+// PlanTopology computes the changes required to make a project match spec
+func (c *Client) PlanTopology(projectID uint64, spec *TopologySpec, opts *TopologyOptions) (*TopologyPlan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &TopologyOptions{}
+	}
+
+	live, err := c.fetchLiveTopology(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &TopologyPlan{
+		ProjectID:      projectID,
+		sourceIDs:      make(map[string]uint64),
+		destinationIDs: make(map[string]uint64),
+	}
+	for slug, s := range live.sources {
+		plan.sourceIDs[slug] = s.ID
+	}
+	for name, d := range live.destinations {
+		plan.destinationIDs[name] = d.ID
+	}
+
+	var creates, updates, deletes []Change
+
+	wantSources := make(map[string]bool)
+	for i := range spec.Sources {
+		want := &spec.Sources[i]
+		wantSources[want.Slug] = true
+		have, ok := live.sources[want.Slug]
+		if !ok {
+			creates = append(creates, Change{Action: ChangeCreate, Kind: KindSource, Name: want.Slug, source: want})
+			continue
+		}
+		if fields := diffSource(have, want); len(fields) > 0 {
+			updates = append(updates, Change{Action: ChangeUpdate, Kind: KindSource, Name: want.Slug, ID: have.ID, Fields: fields, source: want})
+		}
+	}
+
+	wantDestinations := make(map[string]bool)
+	for i := range spec.Destinations {
+		want := &spec.Destinations[i]
+		wantDestinations[want.Name] = true
+		have, ok := live.destinations[want.Name]
+		if !ok {
+			creates = append(creates, Change{Action: ChangeCreate, Kind: KindDestination, Name: want.Name, destination: want})
+			continue
+		}
+		if fields := diffDestination(have, want); len(fields) > 0 {
+			updates = append(updates, Change{Action: ChangeUpdate, Kind: KindDestination, Name: want.Name, ID: have.ID, Fields: fields, destination: want})
+		}
+	}
+
+	wantConnections := make(map[string]bool)
+	for i := range spec.Connections {
+		want := &spec.Connections[i]
+		wantConnections[want.Key()] = true
+		have, ok := live.connections[want.Key()]
+		if !ok {
+			creates = append(creates, Change{Action: ChangeCreate, Kind: KindConnection, Name: want.Key(), connection: want})
+			continue
+		}
+		if fields := diffConnection(have, want); len(fields) > 0 {
+			updates = append(updates, Change{Action: ChangeUpdate, Kind: KindConnection, Name: want.Key(), ID: have.ID, Fields: fields, connection: want})
+		}
+	}
+
+	if opts.Prune {
+		for key, conn := range live.connections {
+			if !wantConnections[key] {
+				deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindConnection, Name: key, ID: conn.ID})
+			}
+		}
+		for _, conn := range live.orphans {
+			deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindConnection, Name: fmt.Sprintf("#%d", conn.ID), ID: conn.ID})
+		}
+		for name, d := range live.destinations {
+			if !wantDestinations[name] {
+				deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindDestination, Name: name, ID: d.ID})
+			}
+		}
+		for slug, s := range live.sources {
+			if !wantSources[slug] {
+				deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindSource, Name: slug, ID: s.ID})
+			}
+		}
+	}
+
+	sortChanges(creates, []ResourceKind{KindSource, KindDestination, KindConnection})
+	sortChanges(updates, []ResourceKind{KindSource, KindDestination, KindConnection})
+	sortChanges(deletes, []ResourceKind{KindConnection, KindDestination, KindSource})
+
+	plan.Changes = append(plan.Changes, creates...)
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, deletes...)
+	return plan, nil
+}
+
+// diffSource returns the fields of a live source that differ from those the
+// spec manages. PlanTopology and Drift share it so they always agree.
+func diffSource(have Source, want *SourceSpec) []FieldChange {
+	var fields []FieldChange
+	fields = diffField(fields, "eps", have.EPS, want.EPS, true)
+	fields = diffField(fields, "auth_type", have.AuthType, want.AuthType, want.AuthType != "")
+	fields = diffField(fields, "status", have.Status, want.Status, want.Status != "")
+	if want.VerifySignature != nil {
+		fields = diffField(fields, "verify_signature", have.VerifySignature, *want.VerifySignature, true)
+	}
+	fields = diffField(fields, "auth_username", have.AuthUsername, want.AuthUsername, want.AuthUsername != "")
+	fields = diffField(fields, "auth_key_name", have.AuthKeyName, want.AuthKeyName, want.AuthKeyName != "")
+	return fields
+}
+
+// diffDestination returns the fields of a live destination that differ from
+// those the spec manages
+func diffDestination(have Destination, want *DestinationSpec) []FieldChange {
+	var fields []FieldChange
+	fields = diffField(fields, "url", have.URL, want.URL, true)
+	fields = diffField(fields, "eps", have.EPS, want.EPS, true)
+	fields = diffField(fields, "status", have.Status, want.Status, want.Status != "")
+	fields = diffField(fields, "method", have.Method, want.Method, want.Method != "")
+	fields = diffField(fields, "headers", formatHeaders(have.Headers), formatHeaders(want.Headers), want.Headers != nil)
+	fields = diffField(fields, "timeout_seconds", have.TimeoutSeconds, want.TimeoutSeconds, want.TimeoutSeconds != 0)
+	fields = diffField(fields, "auth_type", have.AuthType, want.AuthType, want.AuthType != "")
+	fields = diffField(fields, "auth_username", have.AuthUsername, want.AuthUsername, want.AuthUsername != "")
+	return fields
+}
+
+// diffConnection returns the fields of a live connection that differ from
+// those the spec manages
+func diffConnection(have Connection, want *ConnectionSpec) []FieldChange {
+	var fields []FieldChange
+	fields = diffField(fields, "status", have.Status, want.Status, want.Status != "")
+	fields = diffField(fields, "eps", have.EPS, want.EPS, true)
+	fields = diffField(fields, "max_retries", have.MaxRetries, want.MaxRetries, true)
+	fields = diffField(fields, "filters", formatRule(have.Filters), formatRule(want.Filters), true)
+	fields = diffField(fields, "transform", formatRule(have.Transform), formatRule(want.Transform), true)
+	fields = diffField(fields, "retry", formatRule(have.Retry), formatRule(want.Retry), true)
+	return fields
+}
+
+// diffField appends a FieldChange if the field is managed and differs
+func diffField(fields []FieldChange, name string, have, want interface{}, managed bool) []FieldChange {
+	if !managed || have == want {
+		return fields
+	}
+	return append(fields, FieldChange{Field: name, From: have, To: want})
+}
+
+// formatHeaders formats static headers as a sorted, comparable string
+func formatHeaders(headers map[string]string) string {
+	lines := make([]string, 0, len(headers))
+	for name, value := range headers {
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, ", ")
+}
+
+// formatRule encodes connection filters, a transform or a retry strategy as
+// comparable JSON, or "" if there are none
+func formatRule(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" || string(b) == "[]" {
+		return ""
+	}
+	return string(b)
+}
+
+// sortChanges orders changes by kind, then by name, so plans are stable
+func sortChanges(changes []Change, order []ResourceKind) {
+	rank := make(map[ResourceKind]int)
+	for i, k := range order {
+		rank[k] = i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return rank[changes[i].Kind] < rank[changes[j].Kind]
+		}
+		return changes[i].Name < changes[j].Name
+	})
+}
+
+// ApplyResult holds the objects created or updated by ApplyTopology
+type ApplyResult struct {
+	Sources      map[string]Source      `json:"sources"`      // keyed by slug
+	Destinations map[string]Destination `json:"destinations"` // keyed by name
+	Connections  map[string]Connection  `json:"connections"`  // keyed by "source -> destination"
+	Applied      []Change               `json:"applied"`
+}
+
+// ApplyTopology executes a plan in dependency order. It stops at the first
+// failure and returns the changes applied so far along with the error.
+func (c *Client) ApplyTopology(plan *TopologyPlan) (*ApplyResult, error) {
+	result := &ApplyResult{
+		Sources:      make(map[string]Source),
+		Destinations: make(map[string]Destination),
+		Connections:  make(map[string]Connection),
+	}
+
+	sourceIDs := make(map[string]uint64, len(plan.sourceIDs))
+	for k, v := range plan.sourceIDs {
+		sourceIDs[k] = v
+	}
+	destinationIDs := make(map[string]uint64, len(plan.destinationIDs))
+	for k, v := range plan.destinationIDs {
+		destinationIDs[k] = v
+	}
+
+	for _, ch := range plan.Changes {
+		if err := c.applyChange(plan.ProjectID, ch, sourceIDs, destinationIDs, result); err != nil {
+			return result, fmt.Errorf("failed to %s %s %q: %w", ch.Action, ch.Kind, ch.Name, err)
+		}
+		result.Applied = append(result.Applied, ch)
+	}
+
+	return result, nil
+}
+
+func (c *Client) applyChange(projectID uint64, ch Change, sourceIDs, destinationIDs map[string]uint64, result *ApplyResult) error {
+	if ch.Action != ChangeDelete && !ch.hasSpec() {
+		return fmt.Errorf("change has no spec; apply the plan returned by PlanTopology")
+	}
+
+	switch ch.Kind {
+	case KindSource:
+		switch ch.Action {
+		case ChangeCreate:
+			src, err := c.Sources.Create(projectID, CreateSourceRequest{
+				Name:            ch.source.Slug,
+				EPS:             ch.source.EPS,
+				AuthType:        defaultString(ch.source.AuthType, "none"),
+				VerifySignature: ch.source.VerifySignature != nil && *ch.source.VerifySignature,
+				WebhookSecret:   Secret(resolveSecret(ch.source.WebhookSecret)),
+				AuthUsername:    ch.source.AuthUsername,
+				AuthPassword:    Secret(resolveSecret(ch.source.AuthPassword)),
+				AuthKeyName:     ch.source.AuthKeyName,
+				AuthKey:         Secret(resolveSecret(ch.source.AuthKey)),
+			})
+			if err != nil {
+				return err
+			}
+			if ch.source.Status != "" && src.Status != ch.source.Status {
+				if src, err = c.Sources.Update(src.ID, UpdateSourceRequest{Status: ch.source.Status}); err != nil {
+					return err
+				}
+			}
+			sourceIDs[ch.source.Slug] = src.ID
+			result.Sources[ch.source.Slug] = *src
+		case ChangeUpdate:
+			eps := ch.source.EPS
+			src, err := c.Sources.Update(ch.ID, UpdateSourceRequest{
+				EPS:             &eps,
+				AuthType:        ch.source.AuthType,
+				Status:          ch.source.Status,
+				VerifySignature: ch.source.VerifySignature,
+				WebhookSecret:   Secret(resolveSecret(ch.source.WebhookSecret)),
+				AuthUsername:    ch.source.AuthUsername,
+				AuthPassword:    Secret(resolveSecret(ch.source.AuthPassword)),
+				AuthKeyName:     ch.source.AuthKeyName,
+				AuthKey:         Secret(resolveSecret(ch.source.AuthKey)),
+			})
+			if err != nil {
+				return err
+			}
+			result.Sources[ch.source.Slug] = *src
+		case ChangeDelete:
+			return c.Sources.Delete(ch.ID)
+		}
+
+	case KindDestination:
+		switch ch.Action {
+		case ChangeCreate:
+			dest, err := c.Destinations.Create(projectID, CreateDestinationRequest{
+				Name:           ch.destination.Name,
+				URL:            ch.destination.URL,
+				EPS:            ch.destination.EPS,
+				Method:         ch.destination.Method,
+				Headers:        ch.destination.Headers,
+				TimeoutSeconds: ch.destination.TimeoutSeconds,
+				AuthType:       ch.destination.AuthType,
+				AuthUsername:   ch.destination.AuthUsername,
+				AuthPassword:   Secret(resolveSecret(ch.destination.AuthPassword)),
+				AuthToken:      Secret(resolveSecret(ch.destination.AuthToken)),
+				ClientCert:     resolveSecret(ch.destination.ClientCert),
+				ClientKey:      Secret(resolveSecret(ch.destination.ClientKey)),
+			})
+			if err != nil {
+				return err
+			}
+			if ch.destination.Status != "" && dest.Status != ch.destination.Status {
+				if dest, err = c.Destinations.Update(dest.ID, UpdateDestinationRequest{Status: ch.destination.Status}); err != nil {
+					return err
+				}
+			}
+			destinationIDs[ch.destination.Name] = dest.ID
+			result.Destinations[ch.destination.Name] = *dest
+		case ChangeUpdate:
+			eps := ch.destination.EPS
+			var timeout *int
+			if ch.destination.TimeoutSeconds != 0 {
+				timeout = &ch.destination.TimeoutSeconds
+			}
+			// Headers are only managed when the spec lists them; an empty list removes them
+			removeHeaders := ch.destination.Headers != nil && len(ch.destination.Headers) == 0
+			dest, err := c.Destinations.Update(ch.ID, UpdateDestinationRequest{
+				URL:            ch.destination.URL,
+				EPS:            &eps,
+				Status:         ch.destination.Status,
+				Method:         ch.destination.Method,
+				TimeoutSeconds: timeout,
+				Headers:        ch.destination.Headers,
+				RemoveHeaders:  removeHeaders,
+				AuthType:       ch.destination.AuthType,
+				AuthUsername:   ch.destination.AuthUsername,
+				AuthPassword:   Secret(resolveSecret(ch.destination.AuthPassword)),
+				AuthToken:      Secret(resolveSecret(ch.destination.AuthToken)),
+				ClientCert:     resolveSecret(ch.destination.ClientCert),
+				ClientKey:      Secret(resolveSecret(ch.destination.ClientKey)),
+			})
+			if err != nil {
+				return err
+			}
+			result.Destinations[ch.destination.Name] = *dest
+		case ChangeDelete:
+			return c.Destinations.Delete(ch.ID)
+		}
+
+	case KindConnection:
+		switch ch.Action {
+		case ChangeCreate:
+			conn, err := c.Connections.Create(projectID, CreateConnectionRequest{
+				SourceID:      sourceIDs[ch.connection.Source],
+				DestinationID: destinationIDs[ch.connection.Destination],
+				Status:        defaultString(ch.connection.Status, "enabled"),
+				EPS:           ch.connection.EPS,
+				MaxRetries:    ch.connection.MaxRetries,
+				Filters:       ch.connection.Filters,
+				Transform:     ch.connection.Transform,
+				Retry:         ch.connection.Retry,
+			})
+			if err != nil {
+				return err
+			}
+			result.Connections[ch.Name] = *conn
+		case ChangeUpdate:
+			eps := ch.connection.EPS
+			maxRetries := ch.connection.MaxRetries
+			conn, err := c.Connections.Update(ch.ID, UpdateConnectionRequest{
+				Status:          ch.connection.Status,
+				EPS:             &eps,
+				MaxRetries:      &maxRetries,
+				Filters:         ch.connection.Filters,
+				RemoveFilters:   len(ch.connection.Filters) == 0,
+				Transform:       ch.connection.Transform,
+				Retry:           ch.connection.Retry,
+				RemoveTransform: ch.connection.Transform == nil,
+				RemoveRetry:     ch.connection.Retry == nil,
+			})
+			if err != nil {
+				return err
+			}
+			result.Connections[ch.Name] = *conn
+		case ChangeDelete:
+			return c.Connections.Delete(ch.ID)
+		}
+	}
+
+	return nil
+}
+
+// hasSpec reports whether the change carries the spec of its kind
+func (ch Change) hasSpec() bool {
+	switch ch.Kind {
+	case KindSource:
+		return ch.source != nil
+	case KindDestination:
+		return ch.destination != nil
+	case KindConnection:
+		return ch.connection != nil
+	}
+	return false
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
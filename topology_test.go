@@ -0,0 +1,406 @@
+package volley_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+)
+
+// projectBackend is an in-memory project serving the source, destination
+// and connection endpoints used by the topology helpers
+type projectBackend struct {
+	mu           sync.Mutex
+	nextID       uint64
+	sources      map[uint64]*volley.Source
+	destinations map[uint64]*volley.Destination
+	connections  map[uint64]*volley.Connection
+	projects     []volley.Project
+	// owners maps object IDs to the project they belong to
+	owners map[uint64]uint64
+	// orgs records the X-Organization-ID each project was created with
+	orgs  map[uint64]string
+	calls []string
+}
+
+func newProjectBackend() *projectBackend {
+	return &projectBackend{
+		nextID:       100,
+		sources:      make(map[uint64]*volley.Source),
+		destinations: make(map[uint64]*volley.Destination),
+		connections:  make(map[uint64]*volley.Connection),
+		owners:       make(map[uint64]uint64),
+		orgs:         make(map[uint64]string),
+	}
+}
+
+func (b *projectBackend) id() uint64 {
+	b.nextID++
+	return b.nextID
+}
+
+// add* helpers create objects in project 1
+func (b *projectBackend) addSource(slug string, eps int) *volley.Source {
+	return b.createSource(1, slug, eps)
+}
+
+func (b *projectBackend) addDestination(name, url string, eps int) *volley.Destination {
+	return b.createDestination(1, name, url, eps)
+}
+
+func (b *projectBackend) addConnection(sourceID, destinationID uint64, eps, maxRetries int) *volley.Connection {
+	return b.createConnection(1, sourceID, destinationID, eps, maxRetries)
+}
+
+func (b *projectBackend) createSource(projectID uint64, slug string, eps int) *volley.Source {
+	id := b.id()
+	s := &volley.Source{ID: id, Slug: slug, IngestionID: fmt.Sprintf("ing_%s_%d", slug, id), EPS: eps, AuthType: "none", Status: "active"}
+	b.sources[s.ID] = s
+	b.owners[s.ID] = projectID
+	return s
+}
+
+func (b *projectBackend) createDestination(projectID uint64, name, url string, eps int) *volley.Destination {
+	d := &volley.Destination{ID: b.id(), Name: name, URL: url, EPS: eps, Status: "active"}
+	b.destinations[d.ID] = d
+	b.owners[d.ID] = projectID
+	return d
+}
+
+func (b *projectBackend) createConnection(projectID, sourceID, destinationID uint64, eps, maxRetries int) *volley.Connection {
+	c := &volley.Connection{ID: b.id(), SourceID: sourceID, DestinationID: destinationID, Status: "enabled", EPS: eps, MaxRetries: maxRetries}
+	b.connections[c.ID] = c
+	b.owners[c.ID] = projectID
+	return c
+}
+
+func (b *projectBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls = append(b.calls, r.Method+" "+r.URL.Path)
+	w.Header().Set("Content-Type", "application/json")
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	var id uint64
+	if len(parts) > 1 {
+		id, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+
+	reply := func(key string, v interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{key: v})
+	}
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+	}
+
+	switch {
+	case parts[0] == "projects" && len(parts) == 1 && r.Method == http.MethodGet:
+		reply("projects", b.projects)
+
+	case parts[0] == "projects" && len(parts) == 1 && r.Method == http.MethodPost:
+		var req volley.CreateProjectRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		p := volley.Project{ID: b.id(), Name: req.Name}
+		b.projects = append(b.projects, p)
+		b.orgs[p.ID] = r.Header.Get("X-Organization-ID")
+		reply("project", p)
+
+	case parts[0] == "projects" && len(parts) == 3 && r.Method == http.MethodGet:
+		switch parts[2] {
+		case "sources":
+			list := []volley.Source{}
+			for _, s := range b.sources {
+				if b.owners[s.ID] == id {
+					list = append(list, *s)
+				}
+			}
+			reply("sources", list)
+		case "destinations":
+			list := []volley.Destination{}
+			for _, d := range b.destinations {
+				if b.owners[d.ID] == id {
+					list = append(list, *d)
+				}
+			}
+			reply("destinations", list)
+		case "connections":
+			list := []volley.Connection{}
+			for _, c := range b.connections {
+				if b.owners[c.ID] == id {
+					list = append(list, *c)
+				}
+			}
+			reply("connections", list)
+		}
+
+	case parts[0] == "projects" && len(parts) == 3 && r.Method == http.MethodPost:
+		switch parts[2] {
+		case "sources":
+			var req volley.CreateSourceRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			reply("source", b.createSource(id, req.Name, req.EPS))
+		case "destinations":
+			var req volley.CreateDestinationRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			reply("destination", b.createDestination(id, req.Name, req.URL, req.EPS))
+		case "connections":
+			var req volley.CreateConnectionRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			c := b.createConnection(id, req.SourceID, req.DestinationID, req.EPS, req.MaxRetries)
+			c.Status = req.Status
+			reply("connection", c)
+		}
+
+	case parts[0] == "sources":
+		s, ok := b.sources[id]
+		if !ok {
+			notFound()
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			reply("source", s)
+		case http.MethodPut:
+			var req volley.UpdateSourceRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.EPS != nil {
+				s.EPS = *req.EPS
+			}
+			if req.Status != "" {
+				s.Status = req.Status
+			}
+			reply("source", s)
+		case http.MethodDelete:
+			delete(b.sources, id)
+			reply("message", "deleted")
+		}
+
+	case parts[0] == "destinations":
+		d, ok := b.destinations[id]
+		if !ok {
+			notFound()
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			reply("destination", d)
+		case http.MethodPut:
+			var req volley.UpdateDestinationRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.URL != "" {
+				d.URL = req.URL
+			}
+			if req.EPS != nil {
+				d.EPS = *req.EPS
+			}
+			reply("destination", d)
+		case http.MethodDelete:
+			delete(b.destinations, id)
+			reply("message", "deleted")
+		}
+
+	case parts[0] == "connections":
+		c, ok := b.connections[id]
+		if !ok {
+			notFound()
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			reply("connection", c)
+		case http.MethodPut:
+			var req volley.UpdateConnectionRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Status != "" {
+				c.Status = req.Status
+			}
+			if req.EPS != nil {
+				c.EPS = *req.EPS
+			}
+			if req.MaxRetries != nil {
+				c.MaxRetries = *req.MaxRetries
+			}
+			reply("connection", c)
+		case http.MethodDelete:
+			delete(b.connections, id)
+			reply("message", "deleted")
+		}
+
+	default:
+		notFound()
+	}
+}
+
+const testTopology = `
+sources:
+  - slug: stripe
+    eps: 10
+  - slug: github
+    eps: 5
+destinations:
+  - name: api
+    url: https://api.example.com/hooks
+    eps: 5
+connections:
+  - source: stripe
+    destination: api
+    eps: 5
+    max_retries: 3
+  - source: github
+    destination: api
+    eps: 5
+    max_retries: 3
+`
+
+func TestLoadTopologyValidation(t *testing.T) {
+	if _, err := volley.LoadTopology([]byte(testTopology)); err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+
+	// JSON is accepted as well
+	spec, err := volley.LoadTopology([]byte(`{"sources":[{"slug":"a","eps":1}],"destinations":[],"connections":[]}`))
+	if err != nil {
+		t.Fatalf("LoadTopology(JSON) failed: %v", err)
+	}
+	if len(spec.Sources) != 1 || spec.Sources[0].Slug != "a" {
+		t.Errorf("Expected source 'a', got %+v", spec.Sources)
+	}
+
+	_, err = volley.LoadTopology([]byte(`
+sources: []
+destinations: []
+connections:
+  - source: missing
+    destination: api
+`))
+	if err == nil || !strings.Contains(err.Error(), "unknown source") {
+		t.Errorf("Expected unknown source error, got %v", err)
+	}
+
+	// Slugs must be in the form the API creates, or the spec never converges
+	for _, slug := range []string{"Stripe Payments", "stripe--payments", "-stripe", "stripe-"} {
+		spec := &volley.TopologySpec{Sources: []volley.SourceSpec{{Slug: slug}}}
+		if err := spec.Validate(); err == nil {
+			t.Errorf("Expected slug %q to be rejected", slug)
+		}
+	}
+	if err := (&volley.TopologySpec{Sources: []volley.SourceSpec{{Slug: "stripe-payments_v2"}}}).Validate(); err != nil {
+		t.Errorf("Expected a canonical slug to be valid, got %v", err)
+	}
+}
+
+func TestPlanAndApplyTopology(t *testing.T) {
+	backend := newProjectBackend()
+	stripe := backend.addSource("stripe", 1)
+	legacy := backend.addSource("legacy", 1)
+	api := backend.addDestination("api", "https://old.example.com/hooks", 5)
+	backend.addConnection(stripe.ID, api.ID, 5, 3)
+	backend.addConnection(legacy.ID, api.ID, 5, 3)
+
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	spec, err := volley.LoadTopology([]byte(testTopology))
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+
+	plan, err := client.PlanTopology(1, spec, &volley.TopologyOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+
+	var got []string
+	for _, ch := range plan.Changes {
+		got = append(got, fmt.Sprintf("%s %s %s", ch.Action, ch.Kind, ch.Name))
+	}
+	want := []string{
+		"create source github",
+		"create connection github -> api",
+		"update source stripe",
+		"update destination api",
+		"delete connection legacy -> api",
+		"delete source legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Unexpected plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	result, err := client.ApplyTopology(plan)
+	if err != nil {
+		t.Fatalf("ApplyTopology failed: %v", err)
+	}
+	if len(result.Applied) != len(plan.Changes) {
+		t.Errorf("Expected %d applied changes, got %d", len(plan.Changes), len(result.Applied))
+	}
+	if _, ok := result.Connections["github -> api"]; !ok {
+		t.Error("Expected github -> api connection in result")
+	}
+
+	// Re-planning after apply yields no changes
+	plan, err = client.PlanTopology(1, spec, &volley.TopologyOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected empty plan after apply, got:\n%s", plan)
+	}
+}
+
+func TestPlanTopologyWithoutPrune(t *testing.T) {
+	backend := newProjectBackend()
+	backend.addSource("legacy", 1)
+
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	plan, err := client.PlanTopology(1, &volley.TopologySpec{}, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected no deletions without prune, got:\n%s", plan)
+	}
+}
+
+func TestApplyDecodedPlan(t *testing.T) {
+	backend := newProjectBackend()
+	server := createTestServer(backend.ServeHTTP)
+	defer server.Close()
+
+	client := volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+
+	spec, err := volley.LoadTopology([]byte(testTopology))
+	if err != nil {
+		t.Fatalf("LoadTopology failed: %v", err)
+	}
+	plan, err := client.PlanTopology(1, spec, nil)
+	if err != nil {
+		t.Fatalf("PlanTopology failed: %v", err)
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded volley.TopologyPlan
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// A plan that lost its specs is rejected rather than applied
+	result, err := client.ApplyTopology(&decoded)
+	if err == nil || len(result.Applied) != 0 {
+		t.Errorf("Expected an error before any change is applied, got %v (%v)", result, err)
+	}
+}
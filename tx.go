@@ -0,0 +1,184 @@
+package volley
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tx records the resources created through it so they can be deleted again if
+// a later step fails, leaving no orphans behind. Use WithTx, or Begin with
+// Commit or Rollback:
+//
+//	err := client.WithTx(func(tx *volley.Tx) error {
+//		src, err := tx.CreateSource(projectID, volley.CreateSourceRequest{Name: "stripe"})
+//		if err != nil {
+//			return err
+//		}
+//		dest, err := tx.CreateDestination(projectID, volley.CreateDestinationRequest{Name: "api", URL: url})
+//		if err != nil {
+//			return err
+//		}
+//		_, err = tx.CreateConnection(projectID, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID})
+//		return err
+//	})
+type Tx struct {
+	client  *Client
+	created []TxResource
+}
+
+// TxResource identifies a resource created within a transaction
+type TxResource struct {
+	Kind ResourceKind `json:"kind"`
+	ID   uint64       `json:"id"`
+}
+
+func (r TxResource) String() string {
+	return fmt.Sprintf("%s %d", r.Kind, r.ID)
+}
+
+// RollbackError reports a resource that could not be deleted during rollback
+type RollbackError struct {
+	Resource TxResource
+	Err      error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("failed to delete %s: %v", e.Resource, e.Err)
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// TxError is returned when a transaction is rolled back. It wraps the failure
+// that caused the rollback and lists the resources that were deleted and
+// those that were left behind.
+type TxError struct {
+	Err        error
+	RolledBack []TxResource
+	Failed     []*RollbackError
+}
+
+func (e *TxError) Error() string {
+	if len(e.Failed) == 0 {
+		if len(e.RolledBack) == 0 {
+			return e.Err.Error()
+		}
+		rolledBack := make([]string, len(e.RolledBack))
+		for i, res := range e.RolledBack {
+			rolledBack[i] = res.String()
+		}
+		return fmt.Sprintf("%v (rolled back %s)", e.Err, strings.Join(rolledBack, ", "))
+	}
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = f.Error()
+	}
+	return fmt.Sprintf("%v; rollback incomplete: %s", e.Err, strings.Join(failed, "; "))
+}
+
+// Unwrap returns the failure that caused the rollback
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
+// Begin starts a transaction
+func (c *Client) Begin() *Tx {
+	return &Tx{client: c}
+}
+
+// WithTx runs fn in a transaction. If fn returns an error, everything created
+// through tx is deleted in reverse order and a *TxError is returned.
+func (c *Client) WithTx(fn func(tx *Tx) error) error {
+	tx := c.Begin()
+	if err := fn(tx); err != nil {
+		return tx.Rollback(err)
+	}
+	tx.Commit()
+	return nil
+}
+
+// Created returns the resources created so far, in creation order
+func (tx *Tx) Created() []TxResource {
+	return append([]TxResource(nil), tx.created...)
+}
+
+// Commit keeps the created resources and clears the rollback log
+func (tx *Tx) Commit() {
+	tx.created = nil
+}
+
+// Rollback deletes the created resources in reverse order and returns a
+// *TxError wrapping cause. Deletion continues past failures, which are
+// reported in TxError.Failed.
+func (tx *Tx) Rollback(cause error) error {
+	txErr := &TxError{Err: cause}
+	for i := len(tx.created) - 1; i >= 0; i-- {
+		res := tx.created[i]
+		if err := tx.delete(res); err != nil {
+			txErr.Failed = append(txErr.Failed, &RollbackError{Resource: res, Err: err})
+			continue
+		}
+		txErr.RolledBack = append(txErr.RolledBack, res)
+	}
+	tx.created = nil
+	return txErr
+}
+
+func (tx *Tx) delete(res TxResource) error {
+	switch res.Kind {
+	case KindProject:
+		return tx.client.Projects.Delete(res.ID)
+	case KindSource:
+		return tx.client.Sources.Delete(res.ID)
+	case KindDestination:
+		return tx.client.Destinations.Delete(res.ID)
+	case KindConnection:
+		return tx.client.Connections.Delete(res.ID)
+	}
+	return fmt.Errorf("unknown resource kind %q", res.Kind)
+}
+
+func (tx *Tx) record(kind ResourceKind, id uint64) {
+	tx.created = append(tx.created, TxResource{Kind: kind, ID: id})
+}
+
+// CreateProject creates a project and records it for rollback
+func (tx *Tx) CreateProject(req CreateProjectRequest) (*Project, error) {
+	project, err := tx.client.Projects.Create(req)
+	if err != nil {
+		return nil, err
+	}
+	tx.record(KindProject, project.ID)
+	return project, nil
+}
+
+// CreateSource creates a source and records it for rollback
+func (tx *Tx) CreateSource(projectID uint64, req CreateSourceRequest) (*Source, error) {
+	source, err := tx.client.Sources.Create(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	tx.record(KindSource, source.ID)
+	return source, nil
+}
+
+// CreateDestination creates a destination and records it for rollback
+func (tx *Tx) CreateDestination(projectID uint64, req CreateDestinationRequest) (*Destination, error) {
+	dest, err := tx.client.Destinations.Create(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	tx.record(KindDestination, dest.ID)
+	return dest, nil
+}
+
+// CreateConnection creates a connection and records it for rollback
+func (tx *Tx) CreateConnection(projectID uint64, req CreateConnectionRequest) (*Connection, error) {
+	conn, err := tx.client.Connections.Create(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	tx.record(KindConnection, conn.ID)
+	return conn, nil
+}
@@ -0,0 +1,105 @@
+package volley_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/volleyhq/volley-go"
+	"github.com/volleyhq/volley-go/emulator"
+)
+
+// failingServer serves the emulator but fails requests matching method and path
+func failingServer(t *testing.T, failures map[string]bool) *volley.Client {
+	t.Helper()
+	emu := emulator.New(nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures[r.Method+" "+r.URL.Path] {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"internal error"}`))
+			return
+		}
+		emu.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		emu.Close()
+	})
+	return volley.NewClient("test-token", volley.WithBaseURL(server.URL))
+}
+
+// createPipeline creates a source, destination and connection in project 1
+func createPipeline(tx *volley.Tx) error {
+	src, err := tx.CreateSource(1, volley.CreateSourceRequest{Name: "stripe"})
+	if err != nil {
+		return err
+	}
+	dest, err := tx.CreateDestination(1, volley.CreateDestinationRequest{Name: "api", URL: "https://api.example.com"})
+	if err != nil {
+		return err
+	}
+	_, err = tx.CreateConnection(1, volley.CreateConnectionRequest{SourceID: src.ID, DestinationID: dest.ID})
+	return err
+}
+
+func TestWithTxCommit(t *testing.T) {
+	client := failingServer(t, nil)
+
+	if err := client.WithTx(createPipeline); err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	conns, _ := client.GetConnections(1)
+	if len(conns) != 1 {
+		t.Errorf("Expected the connection to be kept, got %d", len(conns))
+	}
+}
+
+func TestWithTxRollback(t *testing.T) {
+	client := failingServer(t, map[string]bool{"POST /api/projects/1/connections": true})
+
+	err := client.WithTx(createPipeline)
+	var txErr *volley.TxError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("Expected *TxError, got %T: %v", err, err)
+	}
+	var apiErr *volley.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorMsg != "internal error" {
+		t.Errorf("Expected the original API error to be wrapped, got %v", err)
+	}
+	if len(txErr.Failed) != 0 {
+		t.Errorf("Expected no rollback failures, got %v", txErr.Failed)
+	}
+	if len(txErr.RolledBack) != 2 || txErr.RolledBack[0].Kind != volley.KindDestination || txErr.RolledBack[1].Kind != volley.KindSource {
+		t.Errorf("Expected destination then source to be rolled back, got %v", txErr.RolledBack)
+	}
+
+	sources, _ := client.ListSources(1)
+	dests, _ := client.ListDestinations(1)
+	if len(sources) != 0 || len(dests) != 0 {
+		t.Errorf("Expected no orphans, got %d sources and %d destinations", len(sources), len(dests))
+	}
+}
+
+func TestTxRollbackFailure(t *testing.T) {
+	client := failingServer(t, map[string]bool{
+		"POST /api/projects/1/connections": true,
+		"DELETE /api/sources/1":            true,
+	})
+
+	err := client.WithTx(createPipeline)
+	var txErr *volley.TxError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("Expected *TxError, got %T: %v", err, err)
+	}
+	if len(txErr.Failed) != 1 || txErr.Failed[0].Resource != (volley.TxResource{Kind: volley.KindSource, ID: 1}) {
+		t.Fatalf("Expected deleting source 1 to fail, got %v", txErr.Failed)
+	}
+	if len(txErr.RolledBack) != 1 {
+		t.Errorf("Expected the destination to still be rolled back, got %v", txErr.RolledBack)
+	}
+	if !strings.Contains(err.Error(), "rollback incomplete: failed to delete source 1") {
+		t.Errorf("Expected the message to name the leftover source, got %q", err.Error())
+	}
+}
@@ -1,6 +1,9 @@
 package volley
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // Organization represents a Volley organization
 type Organization struct {
@@ -8,10 +11,85 @@ type Organization struct {
 	Name      string    `json:"name"`
 	Slug      string    `json:"slug"`
 	AccountID uint64    `json:"account_id"`
-	Role      string    `json:"role"`
+	Role      string    `json:"role"` // the caller's role in the organization, one of the Role constants
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Role is a member's role in an organization
+type Role string
+
+// Organization roles, from most to least privileged
+const (
+	RoleOwner  Role = "owner"  // full access, including deleting the organization
+	RoleAdmin  Role = "admin"  // manages projects, members and invites
+	RoleMember Role = "member" // manages sources, destinations and connections
+	RoleViewer Role = "viewer" // read-only access
+)
+
+// Valid reports whether r is one of the known roles
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// Member is a user's membership in an organization
+type Member struct {
+	ID             uint64    `json:"id"`
+	OrganizationID uint64    `json:"organization_id"`
+	UserID         uint64    `json:"user_id"`
+	Email          string    `json:"email"`
+	Name           string    `json:"name,omitempty"`
+	Role           Role      `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Invite is a pending invitation to join an organization
+type Invite struct {
+	ID             uint64    `json:"id"`
+	OrganizationID uint64    `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           Role      `json:"role"`
+	InvitedBy      uint64    `json:"invited_by"` // user ID of the inviter
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Scope limits what an API token can do
+type Scope string
+
+// API token scopes
+const (
+	ScopeRead  Scope = "read"  // read resources and events
+	ScopeWrite Scope = "write" // create, update and delete resources, replay events
+	ScopeAdmin Scope = "admin" // manage organizations, members and API tokens
+)
+
+// APIToken describes an API token. The secret itself is only returned once,
+// when the token is created.
+type APIToken struct {
+	ID     uint64  `json:"id"`
+	Name   string  `json:"name"`
+	Prefix string  `json:"prefix"` // the first characters of the secret, for identification
+	Scopes []Scope `json:"scopes"`
+	// ExpiresAt is nil for tokens that do not expire
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the token was granted scope
+func (t *APIToken) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // Project represents a Volley project
 type Project struct {
 	ID             uint64    `json:"id"`
@@ -40,27 +118,66 @@ type Source struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
-// Destination represents a webhook destination
+// Destination represents a webhook destination. Auth secrets and the client
+// key are write-only: the API only reports whether they are set.
 type Destination struct {
-	ID        uint64    `json:"id"`
-	Name      string    `json:"name"`
-	URL       string    `json:"url"`
-	EPS       int       `json:"eps"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uint64            `json:"id"`
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	EPS            int               `json:"eps"`
+	Status         string            `json:"status"`
+	Method         string            `json:"method"`                    // "POST", "PUT" or "PATCH"
+	Headers        map[string]string `json:"headers,omitempty"`         // static headers added to every delivery
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // 0 uses the server default
+	AuthType       string            `json:"auth_type"`                 // "none", "bearer" or "basic"
+	AuthUsername   string            `json:"auth_username,omitempty"`
+	AuthSecretSet  bool              `json:"auth_secret_set"`
+	ClientCertSet  bool              `json:"client_cert_set"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Secret is a write-only value such as a password or private key. It is sent
+// to the API as is but redacted when formatted or logged.
+type Secret string
+
+// String returns a redacted placeholder, or "" if the secret is empty
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// GoString redacts the secret for the %#v verb
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// LogValue redacts the secret in log/slog output
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// redacted returns the placeholder as a Secret, for copies of requests that
+// are logged by encoding them rather than formatting them
+func (s Secret) redacted() Secret {
+	return Secret(s.String())
 }
 
 // Connection represents a connection between a source and destination
 type Connection struct {
-	ID          uint64    `json:"id"`
-	SourceID    uint64    `json:"source_id"`
-	DestinationID uint64  `json:"destination_id"`
-	Status      string    `json:"status"`
-	EPS         int       `json:"eps"`
-	MaxRetries  int       `json:"max_retries"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            uint64         `json:"id"`
+	SourceID      uint64         `json:"source_id"`
+	DestinationID uint64         `json:"destination_id"`
+	Status        string         `json:"status"`
+	EPS           int            `json:"eps"`
+	MaxRetries    int            `json:"max_retries"`
+	Filters       []FilterRule   `json:"filters,omitempty"`   // all must match for an event to be delivered
+	Transform     *Transform     `json:"transform,omitempty"` // rewrites delivered bodies
+	Retry         *RetryStrategy `json:"retry,omitempty"`     // nil uses the server's default backoff
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
 }
 
 // Event represents a webhook event/request
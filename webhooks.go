@@ -2,17 +2,37 @@ package volley
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 )
 
-// SendWebhook sends a webhook to a source
-// The sourceID is the ingestion ID provided when you create a source
-func (c *Client) SendWebhook(sourceID string, payload interface{}) (string, error) {
-	path := fmt.Sprintf("/hook/%s", sourceID)
+// SignatureHeader carries a webhook's signature when its source verifies signatures
+const SignatureHeader = "X-Volley-Signature"
+
+// SignWebhook returns the SignatureHeader value for body: "sha256=" followed
+// by the hex HMAC-SHA256 of body keyed with the source's webhook secret
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IngestionService sends webhooks to sources
+type IngestionService service
 
+// URL returns the public URL webhooks are sent to for a source's ingestion ID
+func (s *IngestionService) URL(ingestionID string) string {
+	return fmt.Sprintf("%s/hook/%s", s.client.baseURL, ingestionID)
+}
+
+// Send sends a webhook to a source
+// The sourceID is the ingestion ID provided when you create a source
+func (s *IngestionService) Send(sourceID string, payload interface{}) (string, error) {
 	// Marshal payload to JSON
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -20,8 +40,7 @@ func (c *Client) SendWebhook(sourceID string, payload interface{}) (string, erro
 	}
 
 	// Create request
-	reqURL := c.baseURL + path
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", s.URL(sourceID), bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -32,7 +51,7 @@ func (c *Client) SendWebhook(sourceID string, payload interface{}) (string, erro
 	// If the source has auth configured, include it here
 
 	// Perform request
-	resp, err := c.httpClient.Do(req)
+	resp, err := s.client.send(req, "POST /hook/:ingestion_id", int64(len(jsonData)), 0)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
 	}